@@ -0,0 +1,329 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/moby/moby/client"
+)
+
+// AlertRule is one alert condition from the config file. A rule with a
+// name or label pattern only applies to the containers it matches, and for
+// those it replaces the rules without a pattern on the same metric, which
+// in turn replace the built-in memory rules.
+type AlertRule struct {
+	Metric string   `json:"metric"` // cpu, mem, pids, restarts or health
+	Above  *float64 `json:"above"`  // threshold of a numeric metric; cpu and mem are percentages
+	Is     string   `json:"is"`     // status the health metric alerts on
+	For    string   `json:"for"`    // how long the condition must hold first, e.g. "30s"
+	Level  string   `json:"level"`  // warn (the default) or bad
+	Name   string   `json:"name"`   // glob the container name must match
+	Label  string   `json:"label"`  // label the container must have: "key" or "key=glob"
+}
+
+// alertLevel is how a row is highlighted; higher wins.
+type alertLevel int
+
+const (
+	alertNone alertLevel = iota
+	alertWarn
+	alertBad
+)
+
+// alertMetrics are the values a rule can watch. needsStats metrics only
+// have a value while stats are collected (stats mode or the graphs screen).
+var alertMetrics = map[string]struct {
+	numeric    bool
+	needsStats bool
+}{
+	"cpu":      {true, true},
+	"mem":      {true, true},
+	"pids":     {true, true},
+	"restarts": {true, false},
+	"health":   {false, false},
+}
+
+// healthStates are the values the health metric can take.
+var healthStates = []string{"starting", "healthy", "unhealthy", "none"}
+
+// alertRule is a validated AlertRule.
+type alertRule struct {
+	AlertRule
+	hold  time.Duration
+	level alertLevel
+	rank  int // 0 built-in, 1 global, 2 scoped to a name or label
+}
+
+// defaultAlertRules keep the long-standing memory highlighting when the
+// config doesn't set its own.
+var defaultAlertRules = []AlertRule{
+	{Metric: "mem", Above: ptr(80.0), Level: "warn"},
+	{Metric: "mem", Above: ptr(95.0), Level: "bad"},
+}
+
+func ptr[T any](v T) *T { return &v }
+
+// loadAlertRules validates the rules from the config file and puts them
+// after the built-in ones. All problems are reported together.
+func loadAlertRules(user []AlertRule) ([]alertRule, error) {
+	var rules []alertRule
+	for _, r := range defaultAlertRules {
+		rule, _ := compileAlertRule(r)
+		rule.rank = 0
+		rules = append(rules, rule)
+	}
+	var errs []error
+	for i, r := range user {
+		rule, problems := compileAlertRule(r)
+		for _, err := range problems {
+			errs = append(errs, fmt.Errorf("alerts[%d]: %w", i, err))
+		}
+		if len(problems) == 0 {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, errors.Join(errs...)
+}
+
+// compileAlertRule checks a rule from the config and returns everything
+// wrong with it.
+func compileAlertRule(r AlertRule) (alertRule, []error) {
+	rule := alertRule{AlertRule: r, rank: 1}
+	if r.Name != "" || r.Label != "" {
+		rule.rank = 2
+	}
+	metric, ok := alertMetrics[r.Metric]
+	if !ok {
+		return rule, []error{fmt.Errorf("metric: %q is not one of cpu, mem, pids, restarts, health", r.Metric)}
+	}
+	var errs []error
+	if metric.numeric {
+		if r.Above == nil {
+			errs = append(errs, fmt.Errorf("%s: \"above\" is required", r.Metric))
+		}
+		if r.Is != "" {
+			errs = append(errs, fmt.Errorf("%s: \"is\" only applies to health", r.Metric))
+		}
+	} else {
+		if r.Above != nil {
+			errs = append(errs, errors.New("health: \"above\" doesn't apply, use \"is\""))
+		}
+		valid := false
+		for _, s := range healthStates {
+			valid = valid || r.Is == s
+		}
+		if !valid {
+			errs = append(errs, fmt.Errorf("health: \"is\" must be one of %s", strings.Join(healthStates, ", ")))
+		}
+	}
+	if r.For != "" {
+		d, err := time.ParseDuration(r.For)
+		if err != nil || d < 0 {
+			errs = append(errs, fmt.Errorf("for: %q is not a duration like 30s or 2m", r.For))
+		}
+		rule.hold = d
+	}
+	switch r.Level {
+	case "", "warn":
+		rule.level = alertWarn
+	case "bad":
+		rule.level = alertBad
+	default:
+		errs = append(errs, fmt.Errorf("level: %q is not warn or bad", r.Level))
+	}
+	errs = append(errs, checkPatterns(r.Name, r.Label)...)
+	return rule, errs
+}
+
+// checkPatterns reports malformed globs in the patterns of matchContainer.
+func checkPatterns(name, label string) []error {
+	var errs []error
+	if _, err := path.Match(name, ""); err != nil {
+		errs = append(errs, fmt.Errorf("name: %w", err))
+	}
+	if _, glob, _ := strings.Cut(label, "="); glob != "" {
+		if _, err := path.Match(glob, ""); err != nil {
+			errs = append(errs, fmt.Errorf("label: %w", err))
+		}
+	}
+	return errs
+}
+
+// matches reports whether the rule's name and label patterns select c.
+func (r alertRule) matches(c Container) bool {
+	return matchContainer(c, r.Name, r.Label)
+}
+
+// matchContainer reports whether c's name matches the glob name and it has
+// the label, given as "key" or "key=glob". Empty patterns match anything.
+func matchContainer(c Container, name, label string) bool {
+	if name != "" {
+		if ok, _ := path.Match(name, c.Names); !ok {
+			return false
+		}
+	}
+	if label != "" {
+		key, glob, hasValue := strings.Cut(label, "=")
+		value, ok := c.Labels[key]
+		if !ok {
+			return false
+		}
+		if hasValue {
+			if ok, _ := path.Match(glob, value); !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// alertValue returns the metric a rule watches for c; ok is false while
+// there is nothing to compare, such as the CPU of a stopped container.
+func (m model) alertValue(metric string, c Container) (v float64, health string, ok bool) {
+	if alertMetrics[metric].needsStats {
+		s, have := m.stats[c.key()]
+		if m.statsCollector == nil || !have || c.State != "running" {
+			return 0, "", false
+		}
+		switch metric {
+		case "cpu":
+			return s.CPUPercent, "", true
+		case "mem":
+			if s.MemLimit <= 0 {
+				return 0, "", false
+			}
+			return s.MemUsage / s.MemLimit * 100, "", true
+		case "pids":
+			return float64(s.PIDs), "", true
+		}
+	}
+	switch metric {
+	case "restarts":
+		n, have := m.restartCounts[c.key()]
+		return float64(n), "", have
+	case "health":
+		if c.Health == "" {
+			return 0, "none", true
+		}
+		return 0, c.Health, true
+	}
+	return 0, "", false
+}
+
+// alertKey identifies one rule on one container, by its key.
+type alertKey struct {
+	container string
+	rule      int
+}
+
+// alertHold tracks a rule whose condition currently holds on a container.
+type alertHold struct {
+	since time.Time
+	fired bool // the hold time has passed and the breach was reported
+}
+
+// evaluateAlerts works out which rules currently hold for each container.
+// A rule with a hold time only fires once its condition has held that long
+// without a break, so a brief spike doesn't flash the row. Each breach is
+// handed to the notification rules once, when it starts firing.
+func (m *model) evaluateAlerts(now time.Time) tea.Cmd {
+	holding := make(map[alertKey]bool)
+	levels := make(map[string]alertLevel)
+	var cmds []tea.Cmd
+	for _, c := range m.allContainers {
+		// The most specific rank present per metric is the one that applies
+		rank := make(map[string]int)
+		for _, r := range m.alertRules {
+			if r.matches(c) {
+				rank[r.Metric] = max(rank[r.Metric], r.rank)
+			}
+		}
+		for i, r := range m.alertRules {
+			if !r.matches(c) || r.rank != rank[r.Metric] {
+				continue
+			}
+			v, health, ok := m.alertValue(r.Metric, c)
+			if !ok {
+				continue
+			}
+			if r.Above != nil && v <= *r.Above || r.Above == nil && health != r.Is {
+				continue
+			}
+			key := alertKey{c.key(), i}
+			holding[key] = true
+			hold := m.alertHolds[key]
+			if hold == nil {
+				hold = &alertHold{since: now}
+				m.alertHolds[key] = hold
+			}
+			if now.Sub(hold.since) < r.hold {
+				continue
+			}
+			levels[c.key()] = max(levels[c.key()], r.level)
+			if !hold.fired {
+				hold.fired = true
+				cmds = append(cmds, m.notify(notifyAlert, c, r.describe(v, health), now))
+			}
+		}
+	}
+	for key := range m.alertHolds {
+		if !holding[key] {
+			delete(m.alertHolds, key)
+		}
+	}
+	m.alerts = levels
+	return tea.Batch(cmds...)
+}
+
+// describe says what a firing rule saw, e.g. "cpu 97.2 above 90 for 30s".
+func (r alertRule) describe(v float64, health string) string {
+	s := fmt.Sprintf("%s %s", r.Metric, health)
+	if r.Above != nil {
+		s = fmt.Sprintf("%s %.1f above %g", r.Metric, v, *r.Above)
+	}
+	if r.hold > 0 {
+		s += " for " + r.hold.String()
+	}
+	return s
+}
+
+// alertsWatch reports whether any rule watches the metric.
+func (m model) alertsWatch(metric string) bool {
+	for _, r := range m.alertRules {
+		if r.Metric == metric {
+			return true
+		}
+	}
+	return false
+}
+
+// restartCountsMsg carries the restart counts of some containers, by key.
+type restartCountsMsg map[string]int
+
+// fetchRestartCounts inspects the containers for their restart counts,
+// which the container list doesn't include. Containers that fail to
+// inspect (most likely just removed) are left out.
+func (m model) fetchRestartCounts(containers []Container) tea.Cmd {
+	if len(containers) == 0 {
+		return nil
+	}
+	clients := make([]*client.Client, len(containers))
+	for i, c := range containers {
+		clients[i] = m.clientFor(c)
+	}
+	return func() tea.Msg {
+		counts := make(restartCountsMsg, len(containers))
+		for i, c := range containers {
+			res, err := clients[i].ContainerInspect(context.Background(), c.ID, client.ContainerInspectOptions{})
+			if err == nil {
+				counts[c.key()] = res.Container.RestartCount
+			}
+		}
+		return counts
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadAlertRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    []AlertRule
+		want     []alertRule // the user's rules, after the built-in ones
+		wantErrs []string
+	}{
+		{
+			name: "none",
+		},
+		{
+			name: "defaults filled in",
+			rules: []AlertRule{
+				{Metric: "cpu", Above: ptr(90.0)},
+				{Metric: "health", Is: "unhealthy", For: "30s", Level: "bad", Name: "db-*"},
+				{Metric: "restarts", Above: ptr(3.0), Label: "tier=web*"},
+			},
+			want: []alertRule{
+				{level: alertWarn, rank: 1},
+				{level: alertBad, rank: 2, hold: 30 * time.Second},
+				{level: alertWarn, rank: 2},
+			},
+		},
+		{
+			name:     "unknown metric",
+			rules:    []AlertRule{{Metric: "disk", Above: ptr(1.0)}},
+			wantErrs: []string{`alerts[0]: metric: "disk" is not one of`},
+		},
+		{
+			name:     "numeric metric without a threshold",
+			rules:    []AlertRule{{Metric: "mem", Is: "healthy"}},
+			wantErrs: []string{`alerts[0]: mem: "above" is required`, `alerts[0]: mem: "is" only applies to health`},
+		},
+		{
+			name:     "health with a threshold",
+			rules:    []AlertRule{{Metric: "health", Above: ptr(1.0), Is: "sick"}},
+			wantErrs: []string{`health: "above" doesn't apply`, `health: "is" must be one of`},
+		},
+		{
+			name: "bad duration, level and patterns; good rules are kept",
+			rules: []AlertRule{
+				{Metric: "pids", Above: ptr(100.0), For: "soon", Level: "panic", Name: "[", Label: "k=["},
+				{Metric: "pids", Above: ptr(100.0)},
+			},
+			want:     []alertRule{{level: alertWarn, rank: 1}},
+			wantErrs: []string{`alerts[0]: for: "soon"`, `alerts[0]: level: "panic"`, "alerts[0]: name:", "alerts[0]: label:"},
+		},
+		{
+			name:     "negative duration",
+			rules:    []AlertRule{{Metric: "cpu", Above: ptr(50.0), For: "-1s"}},
+			wantErrs: []string{`for: "-1s"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := loadAlertRules(tt.rules)
+			checkErrs(t, err, tt.wantErrs)
+			if len(rules) < len(defaultAlertRules) {
+				t.Fatalf("got %d rules, want the %d built-in ones first", len(rules), len(defaultAlertRules))
+			}
+			for _, r := range rules[:len(defaultAlertRules)] {
+				if r.rank != 0 {
+					t.Errorf("built-in rule %+v has rank %d, want 0", r.AlertRule, r.rank)
+				}
+			}
+			user := rules[len(defaultAlertRules):]
+			if len(user) != len(tt.want) {
+				t.Fatalf("got %d user rules, want %d", len(user), len(tt.want))
+			}
+			for i, r := range user {
+				w := tt.want[i]
+				if r.level != w.level || r.rank != w.rank || r.hold != w.hold {
+					t.Errorf("rule %d: level %d rank %d hold %v, want level %d rank %d hold %v",
+						i, r.level, r.rank, r.hold, w.level, w.rank, w.hold)
+				}
+			}
+		})
+	}
+}
+
+func TestMatchContainer(t *testing.T) {
+	c := Container{Names: "db-primary", Labels: map[string]string{"tier": "backend", "empty": ""}}
+	tests := []struct {
+		name, label string
+		want        bool
+	}{
+		{"", "", true},
+		{"db-*", "", true},
+		{"web-*", "", false},
+		{"", "tier", true},
+		{"", "empty", true},
+		{"", "missing", false},
+		{"", "tier=back*", true},
+		{"", "tier=front*", false},
+		{"db-*", "tier=backend", true},
+		{"db-*", "tier=frontend", false},
+	}
+	for _, tt := range tests {
+		if got := matchContainer(c, tt.name, tt.label); got != tt.want {
+			t.Errorf("matchContainer(%q, %q) = %v, want %v", tt.name, tt.label, got, tt.want)
+		}
+	}
+}
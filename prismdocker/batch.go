@@ -0,0 +1,296 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/moby/moby/client"
+)
+
+// batchAction is an action that can be run over every selected container.
+type batchAction struct {
+	verb    string // progress label, e.g. "Stopping"
+	done    string // summary label, e.g. "Stopped"
+	applies func(Container) bool
+	run     func(*client.Client, Container) error
+}
+
+var (
+	batchStart = batchAction{
+		verb: "Starting", done: "Started",
+		applies: func(c Container) bool { return c.State != "running" && c.State != "paused" },
+		run:     func(cli *client.Client, c Container) error { return StartContainer(cli, c.ID) },
+	}
+	batchRemove = batchAction{
+		verb: "Removing", done: "Removed",
+		applies: func(c Container) bool { return true },
+		run:     func(cli *client.Client, c Container) error { return RemoveContainer(cli, c.ID) },
+	}
+	// batchPause toggles: running containers are paused, paused ones resumed.
+	batchPause = batchAction{
+		verb: "Pausing/resuming", done: "Paused/resumed",
+		applies: func(c Container) bool { return c.State == "running" || c.State == "paused" },
+		run: func(cli *client.Client, c Container) error {
+			if c.State == "paused" {
+				return UnpauseContainer(cli, c.ID)
+			}
+			return PauseContainer(cli, c.ID)
+		},
+	}
+)
+
+// batchStop stops every running container, giving each timeout seconds
+// to exit (nil for the daemon's default).
+func batchStop(timeout *int) batchAction {
+	return batchAction{
+		verb: "Stopping", done: "Stopped",
+		applies: func(c Container) bool { return c.State == "running" },
+		run:     func(cli *client.Client, c Container) error { return StopContainer(cli, c.ID, timeout) },
+	}
+}
+
+// batchRestart restarts every container, with the same timeout as
+// batchStop.
+func batchRestart(timeout *int) batchAction {
+	return batchAction{
+		verb: "Restarting", done: "Restarted",
+		applies: func(c Container) bool { return true },
+		run:     func(cli *client.Client, c Container) error { return RestartContainer(cli, c.ID, timeout) },
+	}
+}
+
+// batchKill sends a signal to every running or paused container.
+func batchKill(signal string) batchAction {
+	return batchAction{
+		verb: "Sending SIG" + signal, done: "Sent SIG" + signal + " to",
+		applies: func(c Container) bool { return c.State == "running" || c.State == "paused" },
+		run:     func(cli *client.Client, c Container) error { return KillContainer(cli, c.ID, signal) },
+	}
+}
+
+// batchState is the progress of one container within a batch.
+type batchState int
+
+const (
+	batchPending batchState = iota
+	batchOK
+	batchFailed
+)
+
+type batchFailure struct {
+	name string
+	err  error
+}
+
+// batchRun tracks an action running concurrently over several containers.
+type batchRun struct {
+	id       int
+	action   batchAction
+	total    int
+	finished int
+	state    map[string]batchState // by container key
+	failures []batchFailure
+}
+
+func (b *batchRun) running() bool {
+	return b != nil && b.finished < b.total
+}
+
+// batchItemMsg reports the outcome for one container of a batch.
+type batchItemMsg struct {
+	batch int
+	key   string
+	name  string
+	err   error
+}
+
+// selectedContainers returns the marked containers that still exist,
+// sorted by name.
+func (m model) selectedContainers() []Container {
+	var out []Container
+	for _, c := range m.allContainers {
+		if m.selected[c.key()] {
+			out = append(out, c)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Names < out[j].Names })
+	return out
+}
+
+// toggleSelected marks or unmarks a single container.
+func (m *model) toggleSelected(c Container) {
+	if key := c.key(); m.selected[key] {
+		delete(m.selected, key)
+	} else {
+		m.selected[key] = true
+	}
+}
+
+// toggleProjectSelected marks every shown container of a project, or
+// unmarks them all if they already are.
+func (m *model) toggleProjectSelected(project string) {
+	var members []Container
+	all := true
+	for _, c := range m.filteredContainers {
+		if c.Project == project {
+			members = append(members, c)
+			all = all && m.selected[c.key()]
+		}
+	}
+	for _, c := range members {
+		if all {
+			delete(m.selected, c.key())
+		} else {
+			m.selected[c.key()] = true
+		}
+	}
+}
+
+// selectAllVisible marks every container currently shown in the table.
+func (m *model) selectAllVisible() {
+	for _, c := range m.visibleContainers() {
+		m.selected[c.key()] = true
+	}
+}
+
+// invertSelection flips the mark on every container currently shown.
+func (m *model) invertSelection() {
+	for _, c := range m.visibleContainers() {
+		m.toggleSelected(c)
+	}
+}
+
+// pruneSelection forgets marks on containers that no longer exist.
+func (m *model) pruneSelection() {
+	if len(m.selected) == 0 {
+		return
+	}
+	alive := make(map[string]bool, len(m.allContainers))
+	for _, c := range m.allContainers {
+		alive[c.key()] = true
+	}
+	for key := range m.selected {
+		if !alive[key] {
+			delete(m.selected, key)
+		}
+	}
+}
+
+// startBatch runs action on every selected container it applies to.
+func (m *model) startBatch(action batchAction) tea.Cmd {
+	return m.runBatch(action, m.selectedContainers())
+}
+
+// runBatch runs action on each of containers it applies to. All requests
+// are issued at once; results come back as batchItemMsg.
+func (m *model) runBatch(action batchAction, containers []Container) tea.Cmd {
+	if m.batch.running() {
+		m.statusMsg = "A batch action is already running"
+		m.statusTick = 3
+		return nil
+	}
+	var targets []Container
+	for _, c := range containers {
+		if action.applies(c) {
+			targets = append(targets, c)
+		}
+	}
+	if len(targets) == 0 {
+		m.statusMsg = "Nothing to act on"
+		m.statusTick = 3
+		return nil
+	}
+
+	m.batchSeq++
+	m.batch = &batchRun{
+		id:     m.batchSeq,
+		action: action,
+		total:  len(targets),
+		state:  make(map[string]batchState, len(targets)),
+	}
+	cmds := make([]tea.Cmd, 0, len(targets))
+	for _, c := range targets {
+		c := c
+		m.batch.state[c.key()] = batchPending
+		id, cli := m.batch.id, m.clientFor(c)
+		cmds = append(cmds, func() tea.Msg {
+			return batchItemMsg{batch: id, key: c.key(), name: c.Names, err: action.run(cli, c)}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// finishBatchItem records one result. When the batch is complete the
+// succeeded containers are unmarked, so a retry only hits the failures.
+func (m *model) finishBatchItem(msg batchItemMsg) tea.Cmd {
+	b := m.batch
+	if b == nil || msg.batch != b.id {
+		return nil
+	}
+	b.finished++
+	if msg.err != nil {
+		b.state[msg.key] = batchFailed
+		b.failures = append(b.failures, batchFailure{msg.name, msg.err})
+	} else {
+		b.state[msg.key] = batchOK
+	}
+	if b.running() {
+		return nil
+	}
+
+	for key, st := range b.state {
+		if st == batchOK {
+			delete(m.selected, key)
+		}
+	}
+	if len(b.failures) > 0 {
+		m.batchSummary = true
+	} else {
+		m.statusMsg = fmt.Sprintf("%s %d container(s).", b.action.done, b.total)
+		m.statusTick = 3
+	}
+	return m.refreshContainers()
+}
+
+// progress is the footer line shown while a batch runs.
+func (b *batchRun) progress() string {
+	s := fmt.Sprintf("%s %d/%d...", b.action.verb, b.finished, b.total)
+	if n := len(b.failures); n > 0 {
+		s += fmt.Sprintf(" (%d failed)", n)
+	}
+	return s
+}
+
+// summary lists what failed in a finished batch.
+func (b *batchRun) summary() string {
+	sort.Slice(b.failures, func(i, j int) bool { return b.failures[i].name < b.failures[j].name })
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s %d of %d container(s); %d failed:\n", b.action.done, b.total-len(b.failures), b.total, len(b.failures))
+	for _, f := range b.failures {
+		fmt.Fprintf(&sb, "\n  ✗ %s: %s", f.name, f.err)
+	}
+	return sb.String()
+}
+
+// rowMark is the second character of a row's gutter: batch progress while a
+// batch touches the container, otherwise whether it is selected.
+func (m model) rowMark(key string) string {
+	if m.batch != nil {
+		if st, ok := m.batch.state[key]; ok && (m.batch.running() || m.batchSummary) {
+			switch st {
+			case batchPending:
+				return "…"
+			case batchOK:
+				return statusUpStyle.Render("✓")
+			case batchFailed:
+				return statusExitedStyle.Render("✗")
+			}
+		}
+	}
+	if m.selected[key] {
+		return "●"
+	}
+	return " "
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/moby/moby/client"
+)
+
+// runCmd runs cmd and any batch it expands to, returning the messages.
+func runCmd(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		var msgs []tea.Msg
+		for _, c := range batch {
+			msgs = append(msgs, runCmd(c)...)
+		}
+		return msgs
+	}
+	return []tea.Msg{msg}
+}
+
+func TestRunBatch(t *testing.T) {
+	// testStop stops running containers and fails on the ones named bad-*
+	testStop := batchAction{
+		verb: "Stopping", done: "Stopped",
+		applies: func(c Container) bool { return c.State == "running" },
+		run: func(_ *client.Client, c Container) error {
+			if strings.HasPrefix(c.Names, "bad") {
+				return errors.New("permission denied")
+			}
+			return nil
+		},
+	}
+	containers := []Container{
+		{ID: "a1", Names: "web", State: "running"},
+		{ID: "b2", Names: "bad-db", State: "running"},
+		{ID: "c3", Names: "worker", State: "running"},
+		{ID: "d4", Names: "old", State: "exited"},
+	}
+	tests := []struct {
+		name         string
+		selected     []string
+		wantSelected []string
+		wantStatus   string
+		wantSummary  string
+	}{
+		{
+			name:         "all succeed",
+			selected:     []string{"a1", "c3", "d4"},
+			wantSelected: []string{"d4"},
+			wantStatus:   "Stopped 2 container(s).",
+		},
+		{
+			name:         "mixed: the failures stay marked for a retry",
+			selected:     []string{"a1", "b2", "c3"},
+			wantSelected: []string{"b2"},
+			wantSummary:  "Stopped 2 of 3 container(s); 1 failed:\n\n  ✗ bad-db: permission denied",
+		},
+		{
+			name:         "nothing applies",
+			selected:     []string{"d4"},
+			wantSelected: []string{"d4"},
+			wantStatus:   "Nothing to act on",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := model{allContainers: containers, selected: make(map[string]bool)}
+			for _, key := range tt.selected {
+				m.selected[key] = true
+			}
+			for _, msg := range runCmd(m.startBatch(testStop)) {
+				m.finishBatchItem(msg.(batchItemMsg))
+			}
+
+			var selected []string
+			for key := range m.selected {
+				selected = append(selected, key)
+			}
+			sort.Strings(selected)
+			if !reflect.DeepEqual(selected, tt.wantSelected) {
+				t.Errorf("selected = %v, want %v", selected, tt.wantSelected)
+			}
+			if m.statusMsg != tt.wantStatus {
+				t.Errorf("status = %q, want %q", m.statusMsg, tt.wantStatus)
+			}
+			if m.batchSummary != (tt.wantSummary != "") {
+				t.Errorf("summary shown = %v, want %v", m.batchSummary, tt.wantSummary != "")
+			}
+			if tt.wantSummary != "" {
+				if got := m.batch.summary(); got != tt.wantSummary {
+					t.Errorf("summary = %q, want %q", got, tt.wantSummary)
+				}
+			}
+		})
+	}
+}
+
+func TestFinishBatchItem(t *testing.T) {
+	newModel := func() model {
+		return model{
+			selected: map[string]bool{"a1": true, "b2": true},
+			batch: &batchRun{
+				id:     2,
+				action: batchStart,
+				total:  2,
+				state:  map[string]batchState{"a1": batchPending, "b2": batchPending},
+			},
+		}
+	}
+	tests := []struct {
+		name         string
+		msgs         []batchItemMsg
+		wantState    map[string]batchState
+		wantSelected map[string]bool
+		wantProgress string
+	}{
+		{
+			name:         "a result from an earlier batch is ignored",
+			msgs:         []batchItemMsg{{batch: 1, key: "a1", name: "web"}},
+			wantState:    map[string]batchState{"a1": batchPending, "b2": batchPending},
+			wantSelected: map[string]bool{"a1": true, "b2": true},
+			wantProgress: "Starting 0/2...",
+		},
+		{
+			name:         "marks stay until the batch is complete",
+			msgs:         []batchItemMsg{{batch: 2, key: "a1", name: "web"}},
+			wantState:    map[string]batchState{"a1": batchOK, "b2": batchPending},
+			wantSelected: map[string]bool{"a1": true, "b2": true},
+			wantProgress: "Starting 1/2...",
+		},
+		{
+			name: "failures are counted as they come",
+			msgs: []batchItemMsg{
+				{batch: 2, key: "b2", name: "db", err: errors.New("no such image")},
+			},
+			wantState:    map[string]batchState{"a1": batchPending, "b2": batchFailed},
+			wantSelected: map[string]bool{"a1": true, "b2": true},
+			wantProgress: "Starting 1/2... (1 failed)",
+		},
+		{
+			name: "complete",
+			msgs: []batchItemMsg{
+				{batch: 2, key: "b2", name: "db", err: errors.New("no such image")},
+				{batch: 2, key: "a1", name: "web"},
+			},
+			wantState:    map[string]batchState{"a1": batchOK, "b2": batchFailed},
+			wantSelected: map[string]bool{"b2": true},
+			wantProgress: "Starting 2/2... (1 failed)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newModel()
+			for _, msg := range tt.msgs {
+				m.finishBatchItem(msg)
+			}
+			if !reflect.DeepEqual(m.batch.state, tt.wantState) {
+				t.Errorf("state = %v, want %v", m.batch.state, tt.wantState)
+			}
+			if !reflect.DeepEqual(m.selected, tt.wantSelected) {
+				t.Errorf("selected = %v, want %v", m.selected, tt.wantSelected)
+			}
+			if got := m.batch.progress(); got != tt.wantProgress {
+				t.Errorf("progress = %q, want %q", got, tt.wantProgress)
+			}
+		})
+	}
+}
+
+func TestRunBatchRefusesWhileRunning(t *testing.T) {
+	m := model{
+		allContainers: []Container{{ID: "a1", Names: "web", State: "exited"}},
+		selected:      map[string]bool{"a1": true},
+		batch:         &batchRun{id: 1, total: 2, finished: 1},
+		batchSeq:      1,
+	}
+	if cmd := m.startBatch(batchStart); cmd != nil {
+		t.Error("started a second batch while one is running")
+	}
+	if m.batch.id != 1 || m.statusMsg != "A batch action is already running" {
+		t.Errorf("batch %d, status %q", m.batch.id, m.statusMsg)
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"maps"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/moby/moby/client"
+)
+
+// statsCollector keeps one streaming stats subscription per running
+// container. Each stream overwrites its container's latest sample as it
+// arrives; the model picks up a snapshot of all of them once per tick, so
+// the cost of a refresh doesn't grow with the number of containers.
+type statsCollector struct {
+	session int
+
+	mu     sync.Mutex
+	subs   map[string]*statsSub // container key -> its stream
+	latest map[string]Stats     // container key -> newest sample
+}
+
+// statsSub is one container's stream.
+type statsSub struct {
+	cancel context.CancelFunc
+}
+
+// statsTarget is a container to follow: its ID on the daemon cli talks to.
+type statsTarget struct {
+	cli *client.Client
+	id  string
+}
+
+// statsMsg carries a snapshot of the latest sample of every followed
+// container, by key.
+type statsMsg struct {
+	session int
+	stats   map[string]Stats
+}
+
+func newStatsCollector(session int) *statsCollector {
+	return &statsCollector{
+		session: session,
+		subs:    make(map[string]*statsSub),
+		latest:  make(map[string]Stats),
+	}
+}
+
+// sync starts a stream for every listed container that doesn't have one,
+// through the client of its daemon, and stops the streams of containers no
+// longer listed. A stream that ended on its own (the container stopped,
+// the daemon hiccupped) is started again on the next sync if its container
+// is still listed.
+func (c *statsCollector) sync(targets map[string]statsTarget) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, t := range targets {
+		if c.subs[key] == nil {
+			c.start(key, t)
+		}
+	}
+	for key, sub := range c.subs {
+		if _, ok := targets[key]; !ok {
+			sub.cancel()
+			delete(c.subs, key)
+			delete(c.latest, key)
+		}
+	}
+}
+
+// start opens the stream for one container. c.mu must be held.
+func (c *statsCollector) start(key string, t statsTarget) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sub := &statsSub{cancel: cancel}
+	c.subs[key] = sub
+
+	go func() {
+		defer cancel()
+		// Errors are dropped: the container is retried on the next sync,
+		// and until then it simply has no stats.
+		_ = StreamContainerStats(ctx, t.cli, t.id, func(s Stats) bool {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			if c.subs[key] != sub {
+				return false
+			}
+			c.latest[key] = s.withRates(c.latest[key])
+			return true
+		})
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.subs[key] == sub {
+			delete(c.subs, key)
+			delete(c.latest, key)
+		}
+	}()
+}
+
+// snapshot returns a copy of the latest samples.
+func (c *statsCollector) snapshot() map[string]Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return maps.Clone(c.latest)
+}
+
+// Stop closes every stream. It is safe to call on a nil collector.
+func (c *statsCollector) Stop() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, sub := range c.subs {
+		sub.cancel()
+		delete(c.subs, key)
+	}
+	clear(c.latest)
+}
+
+// collectStats hands the collector's latest samples to the model.
+func collectStats(c *statsCollector) tea.Cmd {
+	return func() tea.Msg {
+		return statsMsg{c.session, c.snapshot()}
+	}
+}
+
+// syncStats runs the collector while stats mode or the graphs screen is on
+// and stops it otherwise. It points the collector at the running containers
+// in the list, bar the stale rows of an unreachable host, drops the history
+// of containers that are gone and asks for a snapshot, unless the last one
+// hasn't arrived yet.
+func (m *model) syncStats() tea.Cmd {
+	if !m.showStats && m.activeView != viewStats {
+		m.statsCollector.Stop()
+		m.statsCollector = nil
+		return nil
+	}
+	if m.statsCollector == nil {
+		m.statsSession++
+		m.statsCollector = newStatsCollector(m.statsSession)
+		m.statsPending = false
+	}
+
+	targets := make(map[string]statsTarget)
+	for _, c := range m.filteredContainers {
+		if strings.HasPrefix(strings.ToLower(c.Status), "up") && m.hostErr(c.Host) == nil {
+			targets[c.key()] = statsTarget{m.clientFor(c), c.ID}
+		}
+	}
+	m.statsCollector.sync(targets)
+
+	// Stopped and filtered-out containers keep their history
+	known := make(map[string]bool, len(m.allContainers))
+	for _, c := range m.allContainers {
+		known[c.key()] = true
+	}
+	for key := range m.statsHistory {
+		if !known[key] {
+			delete(m.statsHistory, key)
+		}
+	}
+
+	if m.statsPending {
+		return nil
+	}
+	m.statsPending = true
+	return collectStats(m.statsCollector)
+}
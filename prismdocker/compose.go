@@ -0,0 +1,231 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Labels Docker Compose puts on every container it creates.
+const (
+	composeProjectLabel = "com.docker.compose.project"
+	composeServiceLabel = "com.docker.compose.service"
+)
+
+// projectGroup is one section of the grouped container table: a compose
+// project, or the containers that don't belong to one (Name == "").
+type projectGroup struct {
+	Name    string
+	Running int // running containers in the project, shown or not
+	Total   int
+}
+
+// Title is the label shown on the group's header row.
+func (g *projectGroup) Title() string {
+	if g.Name == "" {
+		return "Ungrouped"
+	}
+	return g.Name
+}
+
+// tableRow is one line of the container table: either a project header or
+// a container.
+type tableRow struct {
+	group     *projectGroup // set on header rows only
+	container Container
+}
+
+func (r tableRow) isHeader() bool { return r.group != nil }
+
+// buildRows lays out the table. Without any compose containers, or with
+// grouping off, it is the plain filtered list. Otherwise every project gets
+// a header followed by its shown containers in the current sort order,
+// unless it is collapsed; ungrouped containers come last in their own
+// section.
+func buildRows(all, filtered []Container, grouped bool, collapsed map[string]bool) []tableRow {
+	groups := make(map[string]*projectGroup)
+	for _, c := range all {
+		g := groups[c.Project]
+		if g == nil {
+			g = &projectGroup{Name: c.Project}
+			groups[c.Project] = g
+		}
+		g.Total++
+		if c.State == "running" {
+			g.Running++
+		}
+	}
+
+	var rows []tableRow
+	if !grouped || (len(groups) == 1 && groups[""] != nil) || len(groups) == 0 {
+		for _, c := range filtered {
+			rows = append(rows, tableRow{container: c})
+		}
+		return rows
+	}
+
+	members := make(map[string][]Container)
+	for _, c := range filtered {
+		members[c.Project] = append(members[c.Project], c)
+	}
+	var names []string
+	for name := range groups {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	// The ungrouped section is only worth a header when it has rows to show
+	if len(members[""]) > 0 {
+		names = append(names, "")
+	}
+
+	for _, name := range names {
+		rows = append(rows, tableRow{group: groups[name]})
+		if collapsed[name] {
+			continue
+		}
+		for _, c := range members[name] {
+			rows = append(rows, tableRow{container: c})
+		}
+	}
+	return rows
+}
+
+// currentRow returns the row under the cursor.
+func (m model) currentRow() (tableRow, bool) {
+	if m.cursor >= 0 && m.cursor < len(m.rows) {
+		return m.rows[m.cursor], true
+	}
+	return tableRow{}, false
+}
+
+// currentContainer returns the container under the cursor; false when the
+// cursor is on a project header or the table is empty.
+func (m model) currentContainer() (Container, bool) {
+	if r, ok := m.currentRow(); ok && !r.isHeader() {
+		return r.container, true
+	}
+	return Container{}, false
+}
+
+// currentGroup returns the project whose header is under the cursor.
+func (m model) currentGroup() (*projectGroup, bool) {
+	if r, ok := m.currentRow(); ok && r.isHeader() {
+		return r.group, true
+	}
+	return nil, false
+}
+
+// visibleContainers returns the containers that have a row in the table,
+// i.e. the filtered list minus collapsed projects.
+func (m model) visibleContainers() []Container {
+	var out []Container
+	for _, r := range m.rows {
+		if !r.isHeader() {
+			out = append(out, r.container)
+		}
+	}
+	return out
+}
+
+// projectContainers returns every container in the project, stopped ones
+// included, sorted by name.
+func (m model) projectContainers(project string) []Container {
+	var out []Container
+	for _, c := range m.allContainers {
+		if c.Project == project {
+			out = append(out, c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Names < out[j].Names })
+	return out
+}
+
+// setCollapsed folds or unfolds a project and keeps the cursor on its
+// header.
+func (m *model) setCollapsed(project string, collapsed bool) {
+	if collapsed {
+		m.collapsed[project] = true
+	} else {
+		delete(m.collapsed, project)
+	}
+	m.refilter()
+	for i, r := range m.rows {
+		if r.isHeader() && r.group.Name == project {
+			m.cursor = i
+			break
+		}
+	}
+	m.scrollToCursor()
+}
+
+// collapseCurrent folds the project under the cursor, whether the cursor is
+// on its header or one of its containers.
+func (m *model) collapseCurrent() {
+	r, ok := m.currentRow()
+	if !ok || !m.grouped() {
+		return
+	}
+	if r.isHeader() {
+		m.setCollapsed(r.group.Name, true)
+	} else {
+		m.setCollapsed(r.container.Project, true)
+	}
+}
+
+// grouped reports whether the table is currently showing project headers.
+func (m model) grouped() bool {
+	return len(m.rows) > 0 && m.rows[0].isHeader()
+}
+
+// runProjectBatch runs action over every container in the project.
+func (m *model) runProjectBatch(g *projectGroup, action batchAction) tea.Cmd {
+	return m.runBatch(action, m.projectContainers(g.Name))
+}
+
+// openProjectLogs follows the logs of every container in the project at
+// once, each line prefixed with its service name.
+func (m *model) openProjectLogs(g *projectGroup) tea.Cmd {
+	var targets []logTarget
+	for _, c := range m.projectContainers(g.Name) {
+		prefix := c.Service
+		if prefix == "" {
+			prefix = c.Names
+		}
+		if c.Host != "" {
+			prefix = c.Host + "/" + prefix
+		}
+		targets = append(targets, logTarget{cli: m.clientFor(c), id: c.ID, prefix: prefix})
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+	return m.startLogs(fmt.Sprintf("%s (%d containers)", g.Title(), len(targets)), targets)
+}
+
+// renderGroupHeader draws a project header row.
+func (m model) renderGroupHeader(g *projectGroup, selected bool, width int) string {
+	arrow := "▾"
+	if m.collapsed[g.Name] {
+		arrow = "▸"
+	}
+	counts := fmt.Sprintf("%d/%d running", g.Running, g.Total)
+	switch {
+	case selected:
+	case g.Running == g.Total:
+		counts = statusUpStyle.Render(counts)
+	case g.Running == 0:
+		counts = statusExitedStyle.Render(counts)
+	default:
+		counts = projectPartialStyle.Render(counts)
+	}
+	style := projectHeaderStyle
+	cursor := "  "
+	if selected {
+		style = selectedStyle.Copy().Bold(true)
+		cursor = "> "
+	}
+	return style.Width(width).Render(cursor + arrow + " " + g.Title() + "  " + counts)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestBuildRows(t *testing.T) {
+	shopDB := Container{ID: "1", Names: "shop-db-1", Project: "shop", State: "running"}
+	shopWeb := Container{ID: "2", Names: "shop-web-1", Project: "shop", State: "exited"}
+	blogWeb := Container{ID: "3", Names: "blog-web-1", Project: "blog", State: "running"}
+	loose := Container{ID: "4", Names: "scratch", State: "running"}
+	all := []Container{shopDB, shopWeb, blogWeb, loose}
+
+	tests := []struct {
+		name      string
+		all       []Container
+		filtered  []Container
+		grouped   bool
+		collapsed map[string]bool
+		want      []string // "Title running/total" for headers, names for containers
+	}{
+		{
+			name:     "grouping off",
+			all:      all,
+			filtered: all,
+			want:     []string{"shop-db-1", "shop-web-1", "blog-web-1", "scratch"},
+		},
+		{
+			name:     "no compose containers",
+			all:      []Container{loose},
+			filtered: []Container{loose},
+			grouped:  true,
+			want:     []string{"scratch"},
+		},
+		{
+			name:     "projects by name, ungrouped last",
+			all:      all,
+			filtered: all,
+			grouped:  true,
+			want:     []string{"blog 1/1", "blog-web-1", "shop 1/2", "shop-db-1", "shop-web-1", "Ungrouped 1/1", "scratch"},
+		},
+		{
+			name:     "counts include hidden containers; empty projects keep their header",
+			all:      all,
+			filtered: []Container{shopDB},
+			grouped:  true,
+			want:     []string{"blog 1/1", "shop 1/2", "shop-db-1"},
+		},
+		{
+			name:      "collapsed",
+			all:       all,
+			filtered:  all,
+			grouped:   true,
+			collapsed: map[string]bool{"shop": true, "": true},
+			want:      []string{"blog 1/1", "blog-web-1", "shop 1/2", "Ungrouped 1/1"},
+		},
+		{
+			name:     "filtered order is kept within a project",
+			all:      all,
+			filtered: []Container{shopWeb, blogWeb, shopDB},
+			grouped:  true,
+			want:     []string{"blog 1/1", "blog-web-1", "shop 1/2", "shop-web-1", "shop-db-1"},
+		},
+		{
+			name:    "empty",
+			grouped: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, r := range buildRows(tt.all, tt.filtered, tt.grouped, tt.collapsed) {
+				if r.isHeader() {
+					got = append(got, fmt.Sprintf("%s %d/%d", r.group.Title(), r.group.Running, r.group.Total))
+				} else {
+					got = append(got, r.container.Names)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rows = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
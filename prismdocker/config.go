@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Config is the user configuration read from config.json.
+type Config struct {
+	// Keys overrides the default bindings: section -> action -> keys.
+	// An empty list unbinds the action.
+	Keys map[string]map[string][]string `json:"keys"`
+
+	// Theme names the theme to start with; dark if empty.
+	Theme string `json:"theme"`
+
+	// Themes defines extra themes by name. Each one starts from a built-in
+	// "base" theme and overrides the colours it lists.
+	Themes map[string]json.RawMessage `json:"themes"`
+
+	// StopTimeout is how long stop and restart wait for a container to
+	// exit before killing it, e.g. "30s"; the daemon's default if empty.
+	StopTimeout string `json:"stop_timeout"`
+
+	// Alerts are the rules that highlight rows. They add to the built-in
+	// memory rules, replacing them if they also watch memory.
+	Alerts []AlertRule `json:"alerts"`
+
+	// Notifications say which container events to report, and how.
+	Notifications []NotifyRule `json:"notifications"`
+
+	// Filters are saved filter queries for the container list, by name;
+	// the filter bar saves them here.
+	Filters map[string]string `json:"filters"`
+
+	// Exec holds the shell options per image repository (the image
+	// reference without tag); the exec dialog saves them here.
+	Exec map[string]ExecOptions `json:"exec"`
+
+	// Hosts are the daemons the hosts view shows together, by name.
+	Hosts map[string]dockerEndpoint `json:"hosts"`
+}
+
+// defaultConfigPath is prismdocker/config.json under the XDG config
+// directory ($XDG_CONFIG_HOME, or ~/.config on Linux).
+func defaultConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "prismdocker", "config.json"), nil
+}
+
+// loadConfig reads the config file at path. A missing file is not an
+// error; it just means the defaults apply. Unknown fields are rejected so
+// a typo doesn't silently do nothing.
+func loadConfig(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// configSavedMsg reports writing a setting to the config file.
+type configSavedMsg struct {
+	done string
+	err  error
+}
+
+// saveConfig writes one top-level field of the config file; done is the
+// status message for when it worked.
+func saveConfig(path, field string, value any, done string) tea.Cmd {
+	return func() tea.Msg {
+		if path == "" {
+			return configSavedMsg{done, errors.New("no config file (no home directory)")}
+		}
+		return configSavedMsg{done, saveConfigField(path, field, value)}
+	}
+}
+
+// saveConfigField sets one top-level field of the config file at path,
+// creating the file if needed. The other fields are kept as they are, but
+// the file is rewritten: fields in alphabetical order, indented by two.
+func saveConfigField(path, field string, value any) error {
+	doc := make(map[string]json.RawMessage)
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return err
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	doc[field] = raw
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// Write beside the file and swap it in, so a crash can't leave half
+	// a config behind
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(out, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,454 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/moby/moby/client"
+)
+
+// defaultContext is the context the Docker CLI always has: whatever
+// DOCKER_HOST and the other environment variables say.
+const defaultContext = "default"
+
+// dockerEndpoint says how to reach a daemon. In the config file it is one
+// of the hosts.
+type dockerEndpoint struct {
+	Host string `json:"host"` // unix://, tcp:// or ssh:// URL; the environment decides if empty
+	// TLS material, paths to PEM files
+	CA            string `json:"ca,omitempty"`
+	Cert          string `json:"cert,omitempty"`
+	Key           string `json:"key,omitempty"`
+	SkipTLSVerify bool   `json:"skip_tls_verify,omitempty"` // don't check the daemon's certificate
+}
+
+// dockerContext is one of the Docker CLI's contexts.
+type dockerContext struct {
+	Name        string
+	Description string
+	Endpoint    dockerEndpoint
+}
+
+// dockerConfigDir is where the Docker CLI keeps its config and contexts:
+// $DOCKER_CONFIG, or ~/.docker.
+func dockerConfigDir() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".docker")
+}
+
+// contextMeta is the part of a context's meta.json that matters here.
+type contextMeta struct {
+	Name     string
+	Metadata struct {
+		Description string
+	}
+	Endpoints map[string]struct {
+		Host          string
+		SkipTLSVerify bool
+	}
+}
+
+// loadDockerContexts reads the Docker CLI's contexts, the default one
+// first and the rest by name, and works out the current one the way the
+// CLI does: $DOCKER_CONTEXT, then the default context if $DOCKER_HOST is
+// set, then currentContext in config.json. Contexts that can't be read
+// are skipped and a current context that doesn't exist falls back to the
+// default one; the problems are returned together as a warning.
+func loadDockerContexts() ([]dockerContext, string, error) {
+	contexts := []dockerContext{{Name: defaultContext, Description: "DOCKER_HOST and the other environment variables"}}
+	dir := dockerConfigDir()
+	if dir == "" {
+		return contexts, defaultContext, nil
+	}
+
+	var errs []error
+	metaDir := filepath.Join(dir, "contexts", "meta")
+	entries, err := os.ReadDir(metaDir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		errs = append(errs, err)
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		c, err := readContext(dir, e.Name())
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		contexts = append(contexts, c)
+	}
+	slices.SortFunc(contexts[1:], func(a, b dockerContext) int { return strings.Compare(a.Name, b.Name) })
+
+	current := defaultContext
+	var cfg struct {
+		CurrentContext string `json:"currentContext"`
+	}
+	path := filepath.Join(dir, "config.json")
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		errs = append(errs, err)
+	}
+	switch {
+	case os.Getenv("DOCKER_CONTEXT") != "":
+		current = os.Getenv("DOCKER_CONTEXT")
+	case os.Getenv("DOCKER_HOST") != "":
+	case cfg.CurrentContext != "":
+		current = cfg.CurrentContext
+	}
+	if findContext(contexts, current) < 0 {
+		errs = append(errs, fmt.Errorf("current context %q does not exist", current))
+		current = defaultContext
+	}
+	return contexts, current, errors.Join(errs...)
+}
+
+// readContext reads the context stored under id, the hash the CLI names
+// its directories by. TLS material is optional and sits in a directory of
+// the same name.
+func readContext(dir, id string) (dockerContext, error) {
+	path := filepath.Join(dir, "contexts", "meta", id, "meta.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return dockerContext{}, err
+	}
+	var meta contextMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return dockerContext{}, fmt.Errorf("%s: %w", path, err)
+	}
+	ep, ok := meta.Endpoints["docker"]
+	if meta.Name == "" || !ok || ep.Host == "" {
+		return dockerContext{}, fmt.Errorf("%s: no name or docker endpoint", path)
+	}
+	c := dockerContext{
+		Name:        meta.Name,
+		Description: meta.Metadata.Description,
+		Endpoint:    dockerEndpoint{Host: ep.Host, SkipTLSVerify: ep.SkipTLSVerify},
+	}
+	tlsDir := filepath.Join(dir, "contexts", "tls", id, "docker")
+	for file, into := range map[string]*string{"ca.pem": &c.Endpoint.CA, "cert.pem": &c.Endpoint.Cert, "key.pem": &c.Endpoint.Key} {
+		if _, err := os.Stat(filepath.Join(tlsDir, file)); err == nil {
+			*into = filepath.Join(tlsDir, file)
+		}
+	}
+	return c, nil
+}
+
+// findContext returns the index of the context called name, or -1.
+func findContext(contexts []dockerContext, name string) int {
+	return slices.IndexFunc(contexts, func(c dockerContext) bool { return c.Name == name })
+}
+
+// newEndpointClient connects to a daemon. SSH endpoints run
+// "docker system dial-stdio" on the remote host, as the Docker CLI does.
+func newEndpointClient(e dockerEndpoint) (*client.Client, error) {
+	if e.Host == "" {
+		return NewDockerClient()
+	}
+	opts := []client.Opt{client.WithAPIVersionNegotiation()}
+	if strings.HasPrefix(e.Host, "ssh://") {
+		u, err := url.Parse(e.Host)
+		if err != nil {
+			return nil, err
+		}
+		// The host name is only a placeholder; every connection goes
+		// through ssh.
+		opts = append(opts,
+			client.WithHost("http://docker.example.com"),
+			client.WithDialContext(func(context.Context, string, string) (net.Conn, error) {
+				return dialSSH(u)
+			}))
+		return client.NewClientWithOpts(opts...)
+	}
+	if e.CA != "" || e.Cert != "" || e.SkipTLSVerify {
+		cfg, err := endpointTLSConfig(e)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, client.WithHTTPClient(&http.Client{Transport: &http.Transport{TLSClientConfig: cfg}}))
+	}
+	opts = append(opts, client.WithHost(e.Host))
+	return client.NewClientWithOpts(opts...)
+}
+
+// endpointTLSConfig loads an endpoint's TLS material. Without a CA the
+// system roots verify the daemon.
+func endpointTLSConfig(e dockerEndpoint) (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: e.SkipTLSVerify}
+	if e.CA != "" {
+		pem, err := os.ReadFile(e.CA)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%s: no certificates found", e.CA)
+		}
+	}
+	if e.Cert != "" || e.Key != "" {
+		cert, err := tls.LoadX509KeyPair(e.Cert, e.Key)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// sshConn is a connection to a remote daemon through the stdin and stdout
+// of an ssh process.
+type sshConn struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *os.File
+	stderr *lockedBuffer
+	exited chan struct{} // closed once ssh has exited and stderr is complete
+	once   sync.Once
+}
+
+// dialSSH starts ssh for the URL. BatchMode makes it fail instead of
+// asking for a password the TUI has nowhere to show.
+func dialSSH(u *url.URL) (net.Conn, error) {
+	args := []string{"-o", "ConnectTimeout=30", "-o", "BatchMode=yes", "-T"}
+	if u.User != nil {
+		args = append(args, "-l", u.User.Username())
+	}
+	if port := u.Port(); port != "" {
+		args = append(args, "-p", port)
+	}
+	args = append(args, "--", u.Hostname(), "docker", "system", "dial-stdio")
+
+	cmd := exec.Command("ssh", args...)
+	c := &sshConn{cmd: cmd, stderr: &lockedBuffer{}, exited: make(chan struct{})}
+	cmd.Stderr = c.stderr
+	// A pipe of our own, so Wait doesn't close stdout under a read
+	stdout, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stdout = w
+	if c.stdin, err = cmd.StdinPipe(); err != nil {
+		stdout.Close()
+		w.Close()
+		return nil, err
+	}
+	err = cmd.Start()
+	w.Close()
+	if err != nil {
+		stdout.Close()
+		return nil, err
+	}
+	c.stdout = stdout
+	go func() {
+		cmd.Wait()
+		close(c.exited)
+	}()
+	return c, nil
+}
+
+// Read returns what ssh printed on stderr once the stream ends, since that
+// says why.
+func (c *sshConn) Read(p []byte) (int, error) {
+	n, err := c.stdout.Read(p)
+	if err != nil && n == 0 {
+		select {
+		case <-c.exited:
+		case <-time.After(time.Second):
+		}
+		if msg := strings.TrimSpace(c.stderr.String()); msg != "" {
+			return 0, errors.New(msg)
+		}
+	}
+	return n, err
+}
+
+func (c *sshConn) Write(p []byte) (int, error) {
+	return c.stdin.Write(p)
+}
+
+func (c *sshConn) Close() error {
+	c.once.Do(func() {
+		c.stdin.Close()
+		c.cmd.Process.Kill()
+		<-c.exited
+		c.stdout.Close()
+	})
+	return nil
+}
+
+func (c *sshConn) LocalAddr() net.Addr              { return sshAddr{} }
+func (c *sshConn) RemoteAddr() net.Addr             { return sshAddr{} }
+func (c *sshConn) SetDeadline(time.Time) error      { return nil }
+func (c *sshConn) SetReadDeadline(time.Time) error  { return nil }
+func (c *sshConn) SetWriteDeadline(time.Time) error { return nil }
+
+type sshAddr struct{}
+
+func (sshAddr) Network() string { return "ssh" }
+func (sshAddr) String() string  { return "ssh" }
+
+// lockedBuffer collects a process's stderr while it is being read.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// ── Switching ──────────────────────────────────────────────────────────
+
+// host describes where a context points, for the picker.
+func (c dockerContext) host() string {
+	if c.Endpoint.Host != "" {
+		return c.Endpoint.Host
+	}
+	if h := os.Getenv("DOCKER_HOST"); h != "" {
+		return h
+	}
+	return client.DefaultDockerHost
+}
+
+// useContext makes name the current context and connects to it. A client
+// that can't be created leaves dockerClient nil and the reason in
+// contextErrs.
+func (m *model) useContext(name string) {
+	m.contextName = name
+	m.dockerClient = nil
+	i := findContext(m.contexts, name)
+	if i < 0 {
+		m.contextErrs[name] = fmt.Errorf("context %q does not exist", name)
+		return
+	}
+	cli, err := newEndpointClient(m.contexts[i].Endpoint)
+	if err != nil {
+		m.contextErrs[name] = err
+		return
+	}
+	m.dockerClient = cli
+}
+
+// contextErr is the last connection error of the current context.
+func (m model) contextErr() error {
+	return m.contextErrs[m.contextName]
+}
+
+// openContexts shows the contexts in a picker with where they point and
+// why they last failed, if they did.
+func (m *model) openContexts() {
+	var options []pickerOption
+	for _, c := range m.contexts {
+		hint := c.host()
+		if c.Name == m.contextName && m.hosts == nil {
+			hint += " (current)"
+		}
+		if err := m.contextErrs[c.Name]; err != nil {
+			hint += " ✖ " + err.Error()
+		}
+		options = append(options, pickerOption{c.Name, hint})
+	}
+	m.openPicker("Docker contexts", options, func(m *model, name string) tea.Cmd {
+		return m.switchContext(name)
+	})
+}
+
+// switchContext connects to the named context, leaving the hosts view if
+// it is on.
+func (m *model) switchContext(name string) tea.Cmd {
+	if name == m.contextName && m.hosts == nil {
+		return nil
+	}
+	m.dropDaemonState()
+	if name != m.contextName {
+		if m.dockerClient != nil {
+			m.dockerClient.Close()
+		}
+		m.useContext(name)
+	}
+	m.statusMsg = "Switched to context " + name
+	m.statusTick = 3
+	if m.dockerClient == nil {
+		return nil
+	}
+	return tea.Batch(fetchContainers(m.dockerClient), subscribeEvents(m.dockerClient, m.eventsSession))
+}
+
+// dropDaemonState drops everything that belongs to the daemons in use —
+// the event stream, stats, logs, shells, a running pull, open dialogs,
+// the hosts view and the lists — before connecting elsewhere.
+func (m *model) dropDaemonState() {
+	m.cancelPull()
+	m.events.Stop()
+	m.events = nil
+	m.eventsLive = false
+	m.eventsSession++
+	m.eventsRetryIn = 0
+	m.eventsBackoff = 1
+	m.statsCollector.Stop()
+	m.statsCollector = nil
+	clear(m.stats)
+	clear(m.statsHistory)
+	m.closeLogs()
+	for _, s := range m.shells {
+		s.Close()
+	}
+	clear(m.shells)
+	clear(m.shellsStarting)
+	m.closeHosts()
+	clear(m.selected)
+	m.batch = nil
+	m.batchSummary = false
+	m.prompt = nil
+	m.confirmMode = false
+	clear(m.alertHolds)
+	clear(m.alerts)
+	clear(m.restartCounts)
+	m.allContainers = nil
+	m.images, m.volumes, m.networks = nil, nil, nil
+	m.cursor, m.tableOffset = 0, 0
+	m.activeView = viewContainers
+	m.refilter()
+}
+
+// onCurrentDaemon reports whether a result that came back through cli
+// belongs to the daemon on screen. Anything else was started before a
+// context switch and is dropped.
+func (m model) onCurrentDaemon(cli *client.Client) bool {
+	return cli != nil && cli == m.dockerClient
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/moby/moby/client"
+)
+
+func TestLoadDockerContexts(t *testing.T) {
+	meta := func(name, host string) string {
+		return `{"Name": "` + name + `", "Metadata": {"Description": "` + name + ` box"}, "Endpoints": {"docker": {"Host": "` + host + `"}}}`
+	}
+	tests := []struct {
+		name          string
+		metas         map[string]string // meta.json by context directory
+		config        string            // config.json, if any
+		dockerContext string
+		dockerHost    string
+		wantNames     []string
+		wantCurrent   string
+		wantErrs      []string
+	}{
+		{
+			name:        "empty config directory",
+			wantNames:   []string{"default"},
+			wantCurrent: "default",
+		},
+		{
+			name: "sorted by name after the default one",
+			metas: map[string]string{
+				"aaa": meta("zeta", "ssh://me@zeta"),
+				"bbb": meta("alpha", "tcp://alpha:2376"),
+			},
+			config:      `{"currentContext": "zeta"}`,
+			wantNames:   []string{"default", "alpha", "zeta"},
+			wantCurrent: "zeta",
+		},
+		{
+			name: "unreadable contexts are skipped",
+			metas: map[string]string{
+				"aaa": `{"Name": `,
+				"bbb": `{"Name": "nohost", "Endpoints": {}}`,
+				"ccc": meta("good", "unix:///run/docker.sock"),
+			},
+			wantNames:   []string{"default", "good"},
+			wantCurrent: "default",
+			wantErrs:    []string{"aaa/meta.json: unexpected end of JSON input", "bbb/meta.json: no name or docker endpoint"},
+		},
+		{
+			name:        "missing current context falls back to the default one",
+			metas:       map[string]string{"aaa": meta("alpha", "tcp://alpha:2376")},
+			config:      `{"currentContext": "gone"}`,
+			wantNames:   []string{"default", "alpha"},
+			wantCurrent: "default",
+			wantErrs:    []string{`current context "gone" does not exist`},
+		},
+		{
+			name:        "malformed config.json",
+			config:      `{`,
+			wantNames:   []string{"default"},
+			wantCurrent: "default",
+			wantErrs:    []string{"config.json: unexpected end of JSON input"},
+		},
+		{
+			name:          "DOCKER_CONTEXT wins",
+			metas:         map[string]string{"aaa": meta("alpha", "tcp://alpha:2376"), "bbb": meta("beta", "tcp://beta:2376")},
+			config:        `{"currentContext": "alpha"}`,
+			dockerContext: "beta",
+			dockerHost:    "tcp://elsewhere:2375",
+			wantNames:     []string{"default", "alpha", "beta"},
+			wantCurrent:   "beta",
+		},
+		{
+			name:        "DOCKER_HOST means the default context",
+			metas:       map[string]string{"aaa": meta("alpha", "tcp://alpha:2376")},
+			config:      `{"currentContext": "alpha"}`,
+			dockerHost:  "tcp://elsewhere:2375",
+			wantNames:   []string{"default", "alpha"},
+			wantCurrent: "default",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Setenv("DOCKER_CONFIG", dir)
+			t.Setenv("DOCKER_CONTEXT", tt.dockerContext)
+			t.Setenv("DOCKER_HOST", tt.dockerHost)
+			for id, data := range tt.metas {
+				writeFile(t, filepath.Join(dir, "contexts", "meta", id, "meta.json"), data)
+			}
+			if tt.config != "" {
+				writeFile(t, filepath.Join(dir, "config.json"), tt.config)
+			}
+
+			contexts, current, err := loadDockerContexts()
+			checkErrs(t, err, tt.wantErrs)
+			var names []string
+			for _, c := range contexts {
+				names = append(names, c.Name)
+			}
+			if !reflect.DeepEqual(names, tt.wantNames) {
+				t.Errorf("contexts = %q, want %q", names, tt.wantNames)
+			}
+			if current != tt.wantCurrent {
+				t.Errorf("current = %q, want %q", current, tt.wantCurrent)
+			}
+		})
+	}
+}
+
+func TestReadContext(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "contexts", "meta", "abc", "meta.json"),
+		`{"Name": "prod", "Metadata": {"Description": "the real thing"}, "Endpoints": {"docker": {"Host": "tcp://prod:2376", "SkipTLSVerify": true}}}`)
+	tlsDir := filepath.Join(dir, "contexts", "tls", "abc", "docker")
+	writeFile(t, filepath.Join(tlsDir, "ca.pem"), "")
+	writeFile(t, filepath.Join(tlsDir, "cert.pem"), "")
+
+	got, err := readContext(dir, "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := dockerContext{
+		Name:        "prod",
+		Description: "the real thing",
+		Endpoint: dockerEndpoint{
+			Host:          "tcp://prod:2376",
+			CA:            filepath.Join(tlsDir, "ca.pem"),
+			Cert:          filepath.Join(tlsDir, "cert.pem"),
+			SkipTLSVerify: true,
+		},
+	}
+	if got != want {
+		t.Errorf("readContext = %+v, want %+v", got, want)
+	}
+}
+
+// writeFile writes data to path, creating the directories on the way.
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResultsFromAnotherDaemonAreDropped(t *testing.T) {
+	old, err := client.NewClientWithOpts(client.WithHost("tcp://127.0.0.1:1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := liveModel(t)
+	m.activeView = viewImages
+	msgs := []any{
+		imagesMsg{cli: old, images: []Image{{ID: "sha256:1"}}},
+		imageActionMsg{cli: old, done: "Removed nginx."},
+		imagePrunePreviewMsg{cli: old, count: 2},
+		pullDoneMsg{cli: old, session: 1},
+		volumesMsg{cli: old, volumes: []Volume{{Name: "data"}}},
+		volumeActionMsg{cli: old, done: "Removed data."},
+		networksMsg{cli: old, networks: []Network{{Name: "front"}}},
+		networkActionMsg{cli: old, done: "Created front."},
+	}
+	m.pull = newImagePull("nginx:latest", 1)
+	for _, msg := range msgs {
+		next, cmd := m.Update(msg)
+		m = next.(model)
+		if cmd != nil {
+			t.Errorf("%T from another daemon started a refetch", msg)
+		}
+	}
+	if m.images != nil || m.volumes != nil || m.networks != nil {
+		t.Errorf("lists from another daemon were kept: %v %v %v", m.images, m.volumes, m.networks)
+	}
+	if m.statusMsg != "" || m.confirmMode || m.pull == nil {
+		t.Errorf("status %q, confirm %v, pull %v; want them untouched", m.statusMsg, m.confirmMode, m.pull)
+	}
+
+	// Once disconnected nothing is current, so nothing is refetched
+	m.dockerClient = nil
+	if _, cmd := m.Update(imageActionMsg{done: "Removed nginx."}); cmd != nil {
+		t.Error("refetched with no daemon")
+	}
+}
+
+func TestDropDaemonStateClearsDialogs(t *testing.T) {
+	cancelled := false
+	m := liveModel(t)
+	m.activeView = viewNetworks
+	m.pull = newImagePull("nginx:latest", 1)
+	m.pull.cancel = func() { cancelled = true }
+	m.prompt = &textPrompt{}
+	m.confirmMode = true
+	m.batch = &batchRun{id: 1, total: 2}
+	m.batchSummary = true
+
+	m.dropDaemonState()
+	if !cancelled || m.pull != nil {
+		t.Errorf("cancelled %v, pull %v; want the pull cancelled and gone", cancelled, m.pull)
+	}
+	if m.prompt != nil || m.confirmMode || m.batch != nil || m.batchSummary {
+		t.Errorf("prompt %v, confirm %v, batch %v, summary %v; want all cleared", m.prompt, m.confirmMode, m.batch, m.batchSummary)
+	}
+	if m.activeView != viewContainers {
+		t.Errorf("view = %d, want the container list", m.activeView)
+	}
+}
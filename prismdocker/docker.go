@@ -3,19 +3,43 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
+	"time"
 
+	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/client"
 )
 
 type Container struct {
-	ID     string
-	Names  string
-	Image  string
-	Status string
-	State  string // "running", "exited", etc.
-	Ports  string
+	ID      string
+	Names   string
+	Image   string
+	ImageID string
+	Status  string
+	State   string // "running", "exited", etc.
+	Ports   string
+	Volumes []string // names of the volumes it mounts
+	// Networks maps each attached network's name to the container's IP
+	// address on it, empty while the container isn't running.
+	Networks map[string]string
+	Labels   map[string]string
+	Health   string // starting, healthy or unhealthy; empty without a healthcheck
+	Project  string // compose project, from the com.docker.compose.project label
+	Service  string // compose service, from the com.docker.compose.service label
+	Host     string // the configured host it runs on, in the hosts view
+}
+
+// key identifies c among the containers shown: its ID, prefixed with its
+// host in the hosts view, where cloned machines can run containers with
+// the same ID. Selection, stats and alerts are kept by key.
+func (c Container) key() string {
+	if c.Host == "" {
+		return c.ID
+	}
+	return c.Host + "/" + c.ID
 }
 
 func NewDockerClient() (*client.Client, error) {
@@ -23,9 +47,23 @@ func NewDockerClient() (*client.Client, error) {
 }
 
 func ListContainers(cli *client.Client) ([]Container, error) {
+	return listContainers(cli, nil)
+}
+
+// GetContainer looks up a single container by ID. The bool result is false
+// when the container no longer exists.
+func GetContainer(cli *client.Client, containerID string) (Container, bool, error) {
+	containers, err := listContainers(cli, make(client.Filters).Add("id", containerID))
+	if err != nil || len(containers) == 0 {
+		return Container{}, false, err
+	}
+	return containers[0], true, nil
+}
+
+func listContainers(cli *client.Client, filters client.Filters) ([]Container, error) {
 	// Use client.ContainerListOptions as indicated by go doc.
 	// If this fails, we will try types.ContainerListOptions.
-	containers, err := cli.ContainerList(context.Background(), client.ContainerListOptions{All: true})
+	containers, err := cli.ContainerList(context.Background(), client.ContainerListOptions{All: true, Filters: filters})
 	if err != nil {
 		return nil, err
 	}
@@ -53,25 +91,92 @@ func ListContainers(cli *client.Client) ([]Container, error) {
 			}
 		}
 
+		var volumes []string
+		for _, mp := range c.Mounts {
+			if mp.Type == "volume" && mp.Name != "" {
+				volumes = append(volumes, mp.Name)
+			}
+		}
+
+		networks := make(map[string]string)
+		if c.NetworkSettings != nil {
+			for name, ep := range c.NetworkSettings.Networks {
+				ip := ""
+				if ep != nil && ep.IPAddress.IsValid() {
+					ip = ep.IPAddress.String()
+				}
+				networks[name] = ip
+			}
+		}
+
 		result = append(result, Container{
-			ID:     c.ID[:12],
-			Names:  names,
-			Image:  c.Image,
-			Status: c.Status,
-			State:  string(c.State),
-			Ports:  strings.Join(ports, ", "),
+			ID:       c.ID[:12],
+			Names:    names,
+			Image:    c.Image,
+			ImageID:  c.ImageID,
+			Status:   c.Status,
+			State:    string(c.State),
+			Ports:    strings.Join(ports, ", "),
+			Volumes:  volumes,
+			Networks: networks,
+			Labels:   c.Labels,
+			Health:   containerHealth(c),
+			Project:  c.Labels[composeProjectLabel],
+			Service:  c.Labels[composeServiceLabel],
 		})
 	}
 	return result, nil
 }
 
+// containerHealth returns the healthcheck status of a listed container.
+// Daemons older than API 1.52 don't report it as a field, only as part of
+// the status text, e.g. "Up 5 minutes (unhealthy)".
+func containerHealth(c container.Summary) string {
+	if c.Health != nil {
+		if c.Health.Status == container.NoHealthcheck {
+			return ""
+		}
+		return string(c.Health.Status)
+	}
+	switch {
+	case strings.HasSuffix(c.Status, "(healthy)"):
+		return string(container.Healthy)
+	case strings.HasSuffix(c.Status, "(unhealthy)"):
+		return string(container.Unhealthy)
+	case strings.HasSuffix(c.Status, "(health: starting)"):
+		return string(container.Starting)
+	}
+	return ""
+}
+
 // Stats represents minimal container statistics
 type Stats struct {
+	Read       time.Time // when the daemon took the sample
 	CPUPercent float64
-	MemUsage   float64 // bytes
+	MemUsage   float64 // bytes, working set: usage minus inactive page cache
 	MemLimit   float64 // bytes
+	MemRSS     float64 // bytes, anonymous memory
+	MemCache   float64 // bytes, page cache, active and inactive
+	MemCgroup  int     // cgroup version the memory numbers come from
+	PIDs       int     // number of processes and threads
 	NetRx      float64 // bytes
 	NetTx      float64 // bytes
+	BlkRead    float64 // bytes, since the container started
+	BlkWrite   float64 // bytes, since the container started
+
+	// Per-second rates since the previous sample; zero for the first one
+	NetRxRate    float64
+	NetTxRate    float64
+	BlkReadRate  float64
+	BlkWriteRate float64
+
+	Interfaces map[string]NetIO // network traffic per interface
+}
+
+// NetIO is the traffic of one network interface.
+type NetIO struct {
+	Rx, Tx         float64 // bytes
+	RxRate, TxRate float64 // bytes per second
 }
 
 // Docker stats JSON structure (simplified)
@@ -142,6 +247,20 @@ type statsJSON struct {
 			TotalWriteback          uint64 `json:"total_writeback"`
 			Unevictable             uint64 `json:"unevictable"`
 			Writeback               uint64 `json:"writeback"`
+
+			// cgroup v2 only; inactive_file, active_file and friends above
+			// are shared with v1
+			Anon              uint64 `json:"anon"`
+			File              uint64 `json:"file"`
+			FileDirty         uint64 `json:"file_dirty"`
+			FileMapped        uint64 `json:"file_mapped"`
+			FileWriteback     uint64 `json:"file_writeback"`
+			KernelStack       uint64 `json:"kernel_stack"`
+			Shmem             uint64 `json:"shmem"`
+			Slab              uint64 `json:"slab"`
+			SlabReclaimable   uint64 `json:"slab_reclaimable"`
+			SlabUnreclaimable uint64 `json:"slab_unreclaimable"`
+			Sock              uint64 `json:"sock"`
 		} `json:"stats"`
 		Limit uint64 `json:"limit"`
 	} `json:"memory_stats"`
@@ -175,13 +294,37 @@ func calculateCPUPercent(v statsJSON) float64 {
 	return cpuPercent
 }
 
+// memCgroupV1 reports whether the memory stats come from cgroup v1, which
+// prefixes the hierarchy-wide counters with total_. v2 has no such keys.
+func memCgroupV1(v statsJSON) bool {
+	s := v.Memory.Stats
+	return s.TotalInactiveFile != 0 || s.TotalCache != 0 || s.TotalRss != 0
+}
+
+// calculateMemUsage returns the working set the way `docker stats` does:
+// usage minus the inactive page cache, which the kernel can reclaim at any
+// time and so shouldn't count against the container. cgroup v1 reports it
+// as total_inactive_file, v2 as inactive_file.
 func calculateMemUsage(v statsJSON) float64 {
-	// Mem usage is slightly different in cgroup v1 vs v2.
-	// Common docker stats: Usage - Cache (v1)
-	// For simplicity, using Usage.
-	// But `docker stats` often does usage - cache for some versions.
-	// Let's use Usage for now.
-	return float64(v.Memory.Usage)
+	usage := v.Memory.Usage
+	inactive := v.Memory.Stats.InactiveFile
+	if memCgroupV1(v) {
+		inactive = v.Memory.Stats.TotalInactiveFile
+	}
+	if inactive < usage {
+		return float64(usage - inactive)
+	}
+	return float64(usage)
+}
+
+// calculateMemBreakdown splits memory into anonymous memory (RSS) and page
+// cache.
+func calculateMemBreakdown(v statsJSON) (rss, cache float64) {
+	s := v.Memory.Stats
+	if memCgroupV1(v) {
+		return float64(s.TotalRss), float64(s.TotalCache)
+	}
+	return float64(s.Anon), float64(s.File)
 }
 
 func calculateNetIO(v statsJSON) (float64, float64) {
@@ -193,31 +336,92 @@ func calculateNetIO(v statsJSON) (float64, float64) {
 	return rx, tx
 }
 
-// GetContainerStats fetches stats for a single container.
-// It uses stream=false to get a one-shot snapshot.
-func GetContainerStats(cli *client.Client, containerID string) (Stats, error) {
-	resp, err := cli.ContainerStats(context.Background(), containerID, client.ContainerStatsOptions{Stream: false})
+// calculateInterfaces returns the traffic of each network interface.
+func calculateInterfaces(v statsJSON) map[string]NetIO {
+	ifaces := make(map[string]NetIO, len(v.Networks))
+	for name, n := range v.Networks {
+		ifaces[name] = NetIO{Rx: float64(n.RxBytes), Tx: float64(n.TxBytes)}
+	}
+	return ifaces
+}
+
+// calculateBlkIO sums the bytes read and written across all block devices.
+// cgroup v1 reports the ops capitalised ("Read"), v2 in lower case.
+func calculateBlkIO(v statsJSON) (float64, float64) {
+	var read, write float64
+	for _, e := range v.Blkio.IoServiceBytesRecursive {
+		switch strings.ToLower(e.Op) {
+		case "read":
+			read += float64(e.Value)
+		case "write":
+			write += float64(e.Value)
+		}
+	}
+	return read, write
+}
+
+// StreamContainerStats follows a container's stats, calling emit with each
+// sample the daemon sends (about one a second) until ctx is cancelled, the
+// stream ends or emit returns false. The daemon sends the first sample
+// without a previous CPU reading, so there is no CPU% to compute from it
+// and it is skipped.
+func StreamContainerStats(ctx context.Context, cli *client.Client, containerID string, emit func(Stats) bool) error {
+	resp, err := cli.ContainerStats(ctx, containerID, client.ContainerStatsOptions{Stream: true})
 	if err != nil {
-		return Stats{}, err
+		return err
 	}
 	defer resp.Body.Close()
 
-	var v statsJSON
-	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
-		return Stats{}, err
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var v statsJSON
+		if err := dec.Decode(&v); err != nil {
+			if errors.Is(err, io.EOF) || ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if v.PreCPU.SystemCPUUsage == 0 {
+			continue
+		}
+		if !emit(statsFromJSON(v)) {
+			return nil
+		}
 	}
+}
 
+// statsFromJSON reduces a daemon stats record to the numbers we show.
+func statsFromJSON(v statsJSON) Stats {
+	read, _ := time.Parse(time.RFC3339Nano, v.Read)
+	rx, tx := calculateNetIO(v)
+	blkRead, blkWrite := calculateBlkIO(v)
+	rss, cache := calculateMemBreakdown(v)
+	cgroup := 2
+	if memCgroupV1(v) {
+		cgroup = 1
+	}
 	return Stats{
+		Read:       read,
 		CPUPercent: calculateCPUPercent(v),
 		MemUsage:   calculateMemUsage(v),
 		MemLimit:   float64(v.Memory.Limit),
-		NetRx:      func() float64 { r, _ := calculateNetIO(v); return r }(),
-		NetTx:      func() float64 { _, t := calculateNetIO(v); return t }(),
-	}, nil
+		MemRSS:     rss,
+		MemCache:   cache,
+		MemCgroup:  cgroup,
+		PIDs:       v.Pids.Current,
+		NetRx:      rx,
+		NetTx:      tx,
+		BlkRead:    blkRead,
+		BlkWrite:   blkWrite,
+		Interfaces: calculateInterfaces(v),
+	}
 }
 
-func StopContainer(cli *client.Client, containerID string) error {
-	_, err := cli.ContainerStop(context.Background(), containerID, client.ContainerStopOptions{})
+// StopContainer gives the container timeout seconds to exit before it is
+// killed; a nil timeout leaves it to the daemon, which waits 10s unless
+// the container sets its own.
+func StopContainer(cli *client.Client, containerID string, timeout *int) error {
+	_, err := cli.ContainerStop(context.Background(), containerID, client.ContainerStopOptions{Timeout: timeout})
 	return err
 }
 
@@ -226,8 +430,10 @@ func StartContainer(cli *client.Client, containerID string) error {
 	return err
 }
 
-func RestartContainer(cli *client.Client, containerID string) error {
-	_, err := cli.ContainerRestart(context.Background(), containerID, client.ContainerRestartOptions{})
+// RestartContainer stops the container the way StopContainer does and
+// starts it again.
+func RestartContainer(cli *client.Client, containerID string, timeout *int) error {
+	_, err := cli.ContainerRestart(context.Background(), containerID, client.ContainerRestartOptions{Timeout: timeout})
 	return err
 }
 
@@ -235,3 +441,83 @@ func RemoveContainer(cli *client.Client, containerID string) error {
 	_, err := cli.ContainerRemove(context.Background(), containerID, client.ContainerRemoveOptions{Force: true})
 	return err
 }
+
+// KillContainer sends a signal, such as "KILL" or "HUP", to the container's
+// main process.
+func KillContainer(cli *client.Client, containerID, signal string) error {
+	_, err := cli.ContainerKill(context.Background(), containerID, client.ContainerKillOptions{Signal: signal})
+	return err
+}
+
+func PauseContainer(cli *client.Client, containerID string) error {
+	_, err := cli.ContainerPause(context.Background(), containerID, client.ContainerPauseOptions{})
+	return err
+}
+
+func UnpauseContainer(cli *client.Client, containerID string) error {
+	_, err := cli.ContainerUnpause(context.Background(), containerID, client.ContainerUnpauseOptions{})
+	return err
+}
+
+// ExecOutput runs cmd in a running container and returns what it wrote to
+// stdout. A non-zero exit status is an error carrying its stderr.
+func ExecOutput(cli *client.Client, containerID string, cmd []string) (string, error) {
+	ctx := context.Background()
+	created, err := cli.ExecCreate(ctx, containerID, client.ExecCreateOptions{
+		Cmd:          cmd,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return "", err
+	}
+	att, err := cli.ExecAttach(ctx, created.ID, client.ExecAttachOptions{})
+	if err != nil {
+		return "", err
+	}
+	defer att.Close()
+
+	var stdout, stderr strings.Builder
+	err = readLogStream(att.Reader, false, func(l logLine) bool {
+		out := &stdout
+		if l.Source == sourceStderr {
+			out = &stderr
+		}
+		out.WriteString(l.Text)
+		out.WriteByte('\n')
+		return true
+	})
+	if err != nil {
+		return "", err
+	}
+
+	code, err := execExitCode(cli, created.ID)
+	if err != nil {
+		return "", err
+	}
+	if code != 0 {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = fmt.Sprintf("exit status %d", code)
+		}
+		return "", errors.New(msg)
+	}
+	return stdout.String(), nil
+}
+
+// execExitCode returns the exit code of a finished exec process. Its output
+// ends just before the process is reaped, so the code may take a moment to
+// show up.
+func execExitCode(cli *client.Client, execID string) (int, error) {
+	for range 20 {
+		res, err := cli.ExecInspect(context.Background(), execID, client.ExecInspectOptions{})
+		if err != nil {
+			return 0, err
+		}
+		if !res.Running {
+			return res.ExitCode, nil
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return 0, errors.New("the process is still running")
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/moby/moby/api/types/container"
+)
+
+func TestCalculateMemUsage(t *testing.T) {
+	tests := []struct {
+		name      string
+		stats     string // the memory_stats object of a stats sample
+		wantUsage float64
+		wantRSS   float64
+		wantCache float64
+	}{
+		{
+			name: "cgroup v1 subtracts total_inactive_file",
+			stats: `{"usage": 1000, "stats": {
+				"inactive_file": 50, "total_inactive_file": 300,
+				"rss": 10, "total_rss": 600, "cache": 20, "total_cache": 400}}`,
+			wantUsage: 700,
+			wantRSS:   600,
+			wantCache: 400,
+		},
+		{
+			name: "cgroup v2 subtracts inactive_file",
+			stats: `{"usage": 1000, "stats": {
+				"inactive_file": 250, "anon": 500, "file": 450}}`,
+			wantUsage: 750,
+			wantRSS:   500,
+			wantCache: 450,
+		},
+		{
+			name:      "inactive cache larger than usage leaves usage alone",
+			stats:     `{"usage": 100, "stats": {"inactive_file": 400}}`,
+			wantUsage: 100,
+		},
+		{
+			name:      "no breakdown",
+			stats:     `{"usage": 4096}`,
+			wantUsage: 4096,
+		},
+		{
+			name: "empty",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v statsJSON
+			if tt.stats != "" {
+				if err := json.Unmarshal([]byte(`{"memory_stats": `+tt.stats+`}`), &v); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := calculateMemUsage(v); got != tt.wantUsage {
+				t.Errorf("calculateMemUsage = %v, want %v", got, tt.wantUsage)
+			}
+			rss, cache := calculateMemBreakdown(v)
+			if rss != tt.wantRSS || cache != tt.wantCache {
+				t.Errorf("calculateMemBreakdown = %v, %v, want %v, %v", rss, cache, tt.wantRSS, tt.wantCache)
+			}
+		})
+	}
+}
+
+func TestContainerHealth(t *testing.T) {
+	tests := []struct {
+		name   string
+		health *container.HealthSummary
+		status string
+		want   string
+	}{
+		{"reported", &container.HealthSummary{Status: container.Unhealthy}, "Up 5 minutes", "unhealthy"},
+		{"reported wins over the status", &container.HealthSummary{Status: container.Healthy}, "Up 5 minutes (unhealthy)", "healthy"},
+		{"no healthcheck", &container.HealthSummary{Status: container.NoHealthcheck}, "Up 5 minutes", ""},
+		{"old daemon, healthy", nil, "Up 5 minutes (healthy)", "healthy"},
+		{"old daemon, unhealthy", nil, "Up 2 hours (unhealthy)", "unhealthy"},
+		{"old daemon, starting", nil, "Up 3 seconds (health: starting)", "starting"},
+		{"old daemon, no healthcheck", nil, "Up 5 minutes", ""},
+		{"old daemon, exited", nil, "Exited (0) 1 hour ago", ""},
+	}
+	for _, tt := range tests {
+		c := container.Summary{Health: tt.health, Status: tt.status}
+		if got := containerHealth(c); got != tt.want {
+			t.Errorf("%s: containerHealth = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
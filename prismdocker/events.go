@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/moby/moby/api/types/events"
+	"github.com/moby/moby/client"
+)
+
+// containerEventActions are the container events that change what the table
+// shows. Everything else (exec_*, attach, resize, ...) is filtered out by the
+// daemon so an idle host sends us nothing at all.
+var containerEventActions = []string{
+	string(events.ActionCreate),
+	string(events.ActionStart),
+	string(events.ActionRestart),
+	string(events.ActionStop),
+	string(events.ActionDie),
+	string(events.ActionOOM),
+	string(events.ActionPause),
+	string(events.ActionUnPause),
+	string(events.ActionRename),
+	string(events.ActionDestroy),
+	string(events.ActionHealthStatus),
+}
+
+// eventStream is a live subscription to the daemon's /events endpoint.
+type eventStream struct {
+	session  int
+	messages <-chan events.Message
+	errs     <-chan error
+	cancel   context.CancelFunc
+}
+
+// Stop cancels the subscription. It is safe to call on a nil stream.
+func (s *eventStream) Stop() {
+	if s != nil {
+		s.cancel()
+	}
+}
+
+// eventsSubscribedMsg reports a newly opened event stream.
+type eventsSubscribedMsg struct{ stream *eventStream }
+
+// containerEventMsg carries a single container event from the stream.
+type containerEventMsg struct {
+	session int
+	event   events.Message
+}
+
+// eventsDroppedMsg is sent when the stream fails to open or breaks.
+type eventsDroppedMsg struct {
+	session int
+	err     error
+}
+
+// containerUpdateMsg carries a freshly listed container after an event.
+// found is false when the container is already gone.
+type containerUpdateMsg struct {
+	cli       *client.Client
+	id        string
+	container Container
+	found     bool
+}
+
+// eventsRetryMax caps the reconnect backoff, in ticks.
+const eventsRetryMax = 16
+
+// eventsSettle is how many ticks a stream must stay up without an event
+// before the reconnect backoff starts over.
+const eventsSettle = 15
+
+func subscribeEvents(cli *client.Client, session int) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithCancel(context.Background())
+		res := cli.Events(ctx, client.EventsListOptions{
+			Filters: make(client.Filters).
+				Add("type", string(events.ContainerEventType)).
+				Add("event", containerEventActions...),
+		})
+		// Events returns before the daemon has answered, so a connection
+		// failure shows up later on the error channel and waitForEvent
+		// reports it as a drop. The backoff is only reset once the stream
+		// has proven itself; see eventsSettle.
+		return eventsSubscribedMsg{&eventStream{
+			session:  session,
+			messages: res.Messages,
+			errs:     res.Err,
+			cancel:   cancel,
+		}}
+	}
+}
+
+func waitForEvent(s *eventStream) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case ev := <-s.messages:
+			return containerEventMsg{s.session, ev}
+		case err := <-s.errs:
+			return eventsDroppedMsg{s.session, err}
+		}
+	}
+}
+
+// fetchContainer re-lists one container so an event can be applied without
+// a full refresh.
+func fetchContainer(cli *client.Client, id string) tea.Cmd {
+	return func() tea.Msg {
+		c, found, err := GetContainer(cli, id)
+		if err != nil {
+			return connErrMsg{cli, err}
+		}
+		return containerUpdateMsg{cli: cli, id: id, container: c, found: found}
+	}
+}
+
+// applyContainerEvent updates the model for one event and returns the
+// follow-up command, if any.
+func (m *model) applyContainerEvent(ev events.Message) tea.Cmd {
+	id := ev.Actor.ID
+	if len(id) > 12 {
+		id = id[:12]
+	}
+	if ev.Action == events.ActionDestroy {
+		m.removeContainer(id)
+		return nil
+	}
+	// The event itself doesn't carry the status text or ports, so look the
+	// container up again; it's one cheap filtered list call.
+	return tea.Batch(m.notifyContainerEvent(ev, id), fetchContainer(m.dockerClient, id))
+}
+
+// upsertContainer replaces the container with the same ID or adds it.
+func (m *model) upsertContainer(c Container) {
+	for i := range m.allContainers {
+		if m.allContainers[i].ID == c.ID {
+			m.allContainers[i] = c
+			m.refilter()
+			return
+		}
+	}
+	m.allContainers = append(m.allContainers, c)
+	m.refilter()
+}
+
+// removeContainer drops the container with the given ID, if present.
+func (m *model) removeContainer(id string) {
+	for i := range m.allContainers {
+		if m.allContainers[i].ID == id {
+			m.allContainers = append(m.allContainers[:i:i], m.allContainers[i+1:]...)
+			delete(m.stats, id)
+			delete(m.statsHistory, id)
+			delete(m.restartCounts, id)
+			delete(m.selected, id)
+			m.refilter()
+			return
+		}
+	}
+}
+
+// refilter re-applies sort, filter and project grouping and keeps the
+// cursor in range.
+func (m *model) refilter() {
+	m.pruneSelection()
+	m.filteredContainers = sortAndFilter(m.allContainers, m.sortOrder, m.showAll, m.stats, m.filter)
+	m.rows = buildRows(m.allContainers, m.filteredContainers, m.groupByProject, m.collapsed)
+	if m.cursor >= len(m.rows) && len(m.rows) > 0 {
+		m.cursor = len(m.rows) - 1
+	} else if len(m.rows) == 0 {
+		m.cursor = 0
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/moby/moby/api/types/events"
+	"github.com/moby/moby/client"
+)
+
+// liveModel is a model with a live event stream of session 1, on a
+// client that is never dialled.
+func liveModel(t *testing.T, containers ...Container) model {
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://127.0.0.1:1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := model{
+		dockerClient:  cli,
+		allContainers: containers,
+		showAll:       true,
+		stats:         map[string]Stats{"a1b2c3d4e5f6": {CPUPercent: 5}},
+		statsHistory:  map[string]*statsRing{"a1b2c3d4e5f6": {}},
+		selected:      map[string]bool{"a1b2c3d4e5f6": true},
+		events:        &eventStream{session: 1, cancel: func() {}},
+		eventsSession: 1,
+		eventsLive:    true,
+		eventsBackoff: 1,
+	}
+	m.refilter()
+	return m
+}
+
+// step feeds msg through Update.
+func step(t *testing.T, m model, msg any) model {
+	t.Helper()
+	next, _ := m.Update(msg)
+	return next.(model)
+}
+
+func TestContainerEvents(t *testing.T) {
+	web := Container{ID: "a1b2c3d4e5f6", Names: "web", State: "running"}
+	db := Container{ID: "0f9e8d7c6b5a", Names: "db", State: "running"}
+	event := func(action events.Action, id string) containerEventMsg {
+		return containerEventMsg{1, events.Message{
+			Type:   events.ContainerEventType,
+			Action: action,
+			Actor:  events.Actor{ID: id + "0123456789abcdef"},
+		}}
+	}
+	tests := []struct {
+		name      string
+		before    []Container
+		event     containerEventMsg
+		update    *containerUpdateMsg // the re-listing the event leads to
+		want      []Container
+		wantStats bool // web's stats, history and mark are kept
+	}{
+		{
+			name:      "create",
+			before:    []Container{web, db},
+			event:     event(events.ActionCreate, "123456abcdef"),
+			update:    &containerUpdateMsg{id: "123456abcdef", container: Container{ID: "123456abcdef", Names: "cache", State: "created"}, found: true},
+			want:      []Container{web, db, {ID: "123456abcdef", Names: "cache", State: "created"}},
+			wantStats: true,
+		},
+		{
+			name:      "die",
+			before:    []Container{web, db},
+			event:     event(events.ActionDie, web.ID),
+			update:    &containerUpdateMsg{id: web.ID, container: Container{ID: web.ID, Names: "web", State: "exited"}, found: true},
+			want:      []Container{{ID: web.ID, Names: "web", State: "exited"}, db},
+			wantStats: true,
+		},
+		{
+			name:      "rename",
+			before:    []Container{web, db},
+			event:     event(events.ActionRename, web.ID),
+			update:    &containerUpdateMsg{id: web.ID, container: Container{ID: web.ID, Names: "frontend", State: "running"}, found: true},
+			want:      []Container{{ID: web.ID, Names: "frontend", State: "running"}, db},
+			wantStats: true,
+		},
+		{
+			name:   "destroy",
+			before: []Container{web, db},
+			event:  event(events.ActionDestroy, web.ID),
+			want:   []Container{db},
+		},
+		{
+			name:   "gone before it could be listed",
+			before: []Container{web, db},
+			event:  event(events.ActionStop, web.ID),
+			update: &containerUpdateMsg{id: web.ID},
+			want:   []Container{db},
+		},
+		{
+			name:      "an event from an old stream is ignored",
+			before:    []Container{web, db},
+			event:     containerEventMsg{0, event(events.ActionDestroy, web.ID).event},
+			want:      []Container{web, db},
+			wantStats: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := step(t, liveModel(t, tt.before...), tt.event)
+			if tt.update != nil {
+				update := *tt.update
+				update.cli = m.dockerClient
+				m = step(t, m, update)
+			}
+			if !reflect.DeepEqual(m.allContainers, tt.want) {
+				t.Errorf("containers = %+v, want %+v", m.allContainers, tt.want)
+			}
+			if len(m.filteredContainers) != len(tt.want) {
+				t.Errorf("%d shown of %d containers", len(m.filteredContainers), len(tt.want))
+			}
+			_, stats := m.stats[web.ID]
+			_, history := m.statsHistory[web.ID]
+			if stats != tt.wantStats || history != tt.wantStats || m.selected[web.ID] != tt.wantStats {
+				t.Errorf("stats %v, history %v, marked %v, want all %v", stats, history, m.selected[web.ID], tt.wantStats)
+			}
+		})
+	}
+}
+
+func TestEventsReconnectBackoff(t *testing.T) {
+	tick := tickMsg{}
+	drop := func(m model) model { return step(t, m, eventsDroppedMsg{session: m.eventsSession}) }
+	subscribe := func(m model) model {
+		return step(t, m, eventsSubscribedMsg{&eventStream{session: m.eventsSession, cancel: func() {}}})
+	}
+	ticks := func(m model, n int) model {
+		for range n {
+			m = step(t, m, tick)
+		}
+		return m
+	}
+	check := func(m model, live bool, session, retryIn, backoff int) {
+		t.Helper()
+		if m.eventsLive != live || m.eventsSession != session || m.eventsRetryIn != retryIn || m.eventsBackoff != backoff {
+			t.Fatalf("live %v, session %d, retry in %d, backoff %d; want %v, %d, %d, %d",
+				m.eventsLive, m.eventsSession, m.eventsRetryIn, m.eventsBackoff, live, session, retryIn, backoff)
+		}
+	}
+
+	m := drop(liveModel(t))
+	check(m, false, 1, 1, 2)
+	m = ticks(m, 1)
+	check(m, false, 2, 0, 2)
+
+	// Each failed attempt doubles the wait
+	m = drop(m)
+	check(m, false, 2, 2, 4)
+	m = ticks(m, 1)
+	check(m, false, 2, 1, 4)
+	m = ticks(m, 1)
+	check(m, false, 3, 0, 4)
+
+	// A stream that opens but breaks straight away doesn't reset it...
+	m = drop(subscribe(m))
+	check(m, false, 3, 4, 8)
+	m = drop(subscribe(ticks(m, 4)))
+	check(m, false, 4, 8, 16)
+	m = drop(subscribe(ticks(m, 8)))
+	check(m, false, 5, 16, 16)
+
+	// ...one that stays up long enough does
+	m = subscribe(ticks(m, 16))
+	check(m, true, 6, 0, 16)
+	m = ticks(m, eventsSettle-1)
+	check(m, true, 6, 0, 16)
+	m = ticks(m, 1)
+	check(m, true, 6, 0, 1)
+
+	// and so does an event
+	m = drop(m)
+	m = subscribe(ticks(m, 1))
+	check(m, true, 7, 0, 2)
+	m = step(t, m, containerEventMsg{7, events.Message{Action: events.ActionDestroy, Actor: events.Actor{ID: "a1b2c3d4e5f6"}}})
+	check(m, true, 7, 0, 1)
+
+	// A drop reported by a stream that was replaced is ignored
+	m = step(t, m, eventsDroppedMsg{session: 6})
+	check(m, true, 7, 0, 1)
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/moby/moby/client"
+)
+
+// ExecOptions say how to start a shell in a container. In the config file
+// they are the defaults for the containers of one image.
+type ExecOptions struct {
+	Command string   `json:"command,omitempty"` // command line, with shell-style quoting; the best shell found if empty
+	User    string   `json:"user,omitempty"`    // user or user:group; the image's user if empty
+	Workdir string   `json:"workdir,omitempty"` // the image's working directory if empty
+	Env     []string `json:"env,omitempty"`     // extra variables, KEY=value
+}
+
+// shellCandidates are the shells probed for, best first.
+var shellCandidates = []string{"bash", "zsh", "ash", "sh"}
+
+// errNoShell is reported for containers with none of the shellCandidates,
+// such as those of distroless images.
+var errNoShell = errors.New("no shell found (tried " + strings.Join(shellCandidates, ", ") + ")")
+
+// loadExecDefaults validates the per-image exec defaults from the config
+// file. All problems are reported together.
+func loadExecDefaults(user map[string]ExecOptions) (map[string]ExecOptions, error) {
+	defaults := make(map[string]ExecOptions, len(user))
+	var errs []error
+	for _, image := range sortedKeys(user) {
+		opts := user[image]
+		problems := opts.check()
+		for _, err := range problems {
+			errs = append(errs, fmt.Errorf("exec[%q]: %w", image, err))
+		}
+		if len(problems) == 0 {
+			defaults[image] = opts
+		}
+	}
+	return defaults, errors.Join(errs...)
+}
+
+// check returns everything wrong with the options.
+func (o ExecOptions) check() []error {
+	var errs []error
+	if _, err := splitCommand(o.Command); err != nil {
+		errs = append(errs, fmt.Errorf("command: %w", err))
+	}
+	for _, kv := range o.Env {
+		if key, _, ok := strings.Cut(kv, "="); !ok || key == "" {
+			errs = append(errs, fmt.Errorf("env: %q is not KEY=value", kv))
+		}
+	}
+	return errs
+}
+
+// splitCommand splits a command line into words the way a shell would,
+// minus expansions: whitespace separates words, quotes group them and a
+// backslash outside single quotes escapes the next character.
+func splitCommand(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, errors.New("trailing backslash")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// imageRepo is the key exec defaults are stored under: the image
+// reference without its tag or digest, so they survive upgrades.
+func imageRepo(ref string) string {
+	ref, _, _ = strings.Cut(ref, "@")
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		ref = ref[:i]
+	}
+	return ref
+}
+
+// execDefaultsFor returns the saved options for a container's image.
+func (m model) execDefaultsFor(c Container) ExecOptions {
+	return m.execDefaults[imageRepo(c.Image)]
+}
+
+// detectShell returns the first of the shellCandidates the container can
+// run, found by running each with a no-op script.
+func detectShell(cli *client.Client, containerID string) (string, error) {
+	for _, sh := range shellCandidates {
+		if _, err := ExecOutput(cli, containerID, []string{sh, "-c", "exit 0"}); err == nil {
+			return sh, nil
+		}
+	}
+	return "", errNoShell
+}
+
+// ── Exec dialog ────────────────────────────────────────────────────────
+
+// execFields are the dialog's inputs, in order.
+var execFields = []struct {
+	label       string
+	placeholder string
+}{
+	{"Command", "auto: " + strings.Join(shellCandidates, ", ")},
+	{"User", "image default"},
+	{"Workdir", "image default"},
+	{"Env", "KEY=value ..."},
+}
+
+// execDialog is the popup that sets the options of a new shell.
+type execDialog struct {
+	container Container
+	values    []string // one per execFields entry
+	focus     int
+	err       string
+}
+
+// openExecDialog shows the exec dialog for the container under the cursor,
+// filled in with its image's defaults.
+func (m *model) openExecDialog() {
+	c, ok := m.currentContainer()
+	if !ok || c.State != "running" {
+		return
+	}
+	if _, ok := m.shells[c.key()]; ok {
+		m.statusMsg = fmt.Sprintf("%s already has a shell; %s reattaches to it", c.Names, m.keys.keysFor("containers", "shell"))
+		m.statusTick = 3
+		return
+	}
+	if m.shellsStarting[c.key()] {
+		m.statusMsg = "Still starting a shell in " + c.Names + "..."
+		m.statusTick = 3
+		return
+	}
+	o := m.execDefaultsFor(c)
+	env := make([]string, len(o.Env))
+	for i, kv := range o.Env {
+		env[i] = quoteWord(kv)
+	}
+	m.execDialog = &execDialog{
+		container: c,
+		values:    []string{o.Command, o.User, o.Workdir, strings.Join(env, " ")},
+	}
+}
+
+// quoteWord quotes s for splitCommand if it needs it.
+func quoteWord(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t'\"\\") {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// options reads the dialog's inputs back.
+func (d *execDialog) options() (ExecOptions, error) {
+	env, err := splitCommand(d.values[3])
+	if err != nil {
+		return ExecOptions{}, fmt.Errorf("env: %w", err)
+	}
+	o := ExecOptions{
+		Command: strings.TrimSpace(d.values[0]),
+		User:    strings.TrimSpace(d.values[1]),
+		Workdir: strings.TrimSpace(d.values[2]),
+		Env:     env,
+	}
+	if errs := o.check(); len(errs) > 0 {
+		return o, errs[0]
+	}
+	return o, nil
+}
+
+// updateExecDialog handles a key while the dialog is open. Its keys are
+// fixed like the prompt's: Tab and the arrows move between fields, Enter
+// starts the shell, Ctrl+S also saves the options for the image, Esc
+// cancels, everything else edits the focused field.
+func (m *model) updateExecDialog(msg tea.KeyMsg) tea.Cmd {
+	d := m.execDialog
+	v := &d.values[d.focus]
+	switch msg.Type {
+	case tea.KeyTab, tea.KeyDown:
+		d.focus = (d.focus + 1) % len(d.values)
+	case tea.KeyShiftTab, tea.KeyUp:
+		d.focus = (d.focus + len(d.values) - 1) % len(d.values)
+	case tea.KeyEnter, tea.KeyCtrlS:
+		o, err := d.options()
+		if err != nil {
+			d.err = err.Error()
+			return nil
+		}
+		m.execDialog = nil
+		cmd := m.startShellWith(d.container, o)
+		if msg.Type == tea.KeyCtrlS {
+			image := imageRepo(d.container.Image)
+			m.execDefaults[image] = o
+			cmd = tea.Batch(cmd, saveConfig(m.configPath, "exec", maps.Clone(m.execDefaults), "Saved the exec options for "+image))
+		}
+		return cmd
+	case tea.KeyEsc:
+		m.execDialog = nil
+	case tea.KeyBackspace:
+		if r := []rune(*v); len(r) > 0 {
+			*v = string(r[:len(r)-1])
+		}
+	case tea.KeyCtrlU:
+		*v = ""
+	case tea.KeySpace:
+		*v += " "
+	case tea.KeyRunes:
+		*v += string(msg.Runes)
+	}
+	return nil
+}
+
+// render draws the dialog as a centered popup.
+func (d *execDialog) render(width, height int) string {
+	lines := []string{titleStyle.Render("Exec in " + d.container.Names), ""}
+	for i, f := range execFields {
+		label := lipgloss.NewStyle().Width(10).Render(f.label)
+		value := d.values[i]
+		if i == d.focus {
+			label = selectedStyle.Render(label)
+			value += "█"
+		}
+		if d.values[i] == "" {
+			value += inspectDimStyle.Render(f.placeholder)
+		}
+		lines = append(lines, label+" "+value)
+	}
+	if d.err != "" {
+		lines = append(lines, "", alertStyle.Render("✖ "+d.err))
+	}
+	lines = append(lines, "",
+		mutedStyle.Render("Tab: Next field • Enter: Start • Esc: Cancel"),
+		mutedStyle.Render("Ctrl+S: Start and save as the default for "+imageRepo(d.container.Image)))
+
+	popup := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(activeTheme.Accent)).
+		Padding(1, 3).
+		Width(min(max(width/2, 60), width-4)).
+		Render(strings.Join(lines, "\n"))
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, popup)
+}
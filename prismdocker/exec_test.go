@@ -0,0 +1,93 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []string
+		wantErr string
+	}{
+		{"", nil, ""},
+		{"   \t ", nil, ""},
+		{"ls -la  /tmp", []string{"ls", "-la", "/tmp"}, ""},
+		{`sh -c 'echo "hi there"'`, []string{"sh", "-c", `echo "hi there"`}, ""},
+		{`echo "it's" here`, []string{"echo", "it's", "here"}, ""},
+		{`a\ b c`, []string{"a b", "c"}, ""},
+		{`"a\"b" 'c\d'`, []string{`a"b`, `c\d`}, ""},
+		{`'' ""`, []string{"", ""}, ""},
+		{`x'y'"z"`, []string{"xyz"}, ""},
+		{`echo 'oops`, nil, "unterminated ' quote"},
+		{`echo "oops`, nil, `unterminated " quote`},
+		{`echo oops\`, nil, "trailing backslash"},
+	}
+	for _, tt := range tests {
+		got, err := splitCommand(tt.in)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("splitCommand(%q) error = %v, want %q", tt.in, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("splitCommand(%q): %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitCommand(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestQuoteWordRoundTrip(t *testing.T) {
+	words := []string{
+		"plain",
+		"",
+		"two words",
+		"tab\there",
+		"it's",
+		`say "hi"`,
+		`back\slash`,
+		`'`,
+		`mixed 'single' "double" \ all`,
+		"KEY=value with spaces",
+	}
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		quoted[i] = quoteWord(w)
+		got, err := splitCommand(quoted[i])
+		if err != nil || len(got) != 1 || got[0] != w {
+			t.Errorf("splitCommand(quoteWord(%q)) = %q, %v, want [%q]", w, got, err, w)
+		}
+	}
+	if quoteWord("plain") != "plain" {
+		t.Errorf("quoteWord(%q) = %q, want it unquoted", "plain", quoteWord("plain"))
+	}
+	line := strings.Join(quoted, " ")
+	got, err := splitCommand(line)
+	if err != nil || !reflect.DeepEqual(got, words) {
+		t.Errorf("splitCommand(%q) = %q, %v, want %q", line, got, err, words)
+	}
+}
+
+func TestImageRepo(t *testing.T) {
+	tests := map[string]string{
+		"nginx":                               "nginx",
+		"nginx:1.27":                          "nginx",
+		"library/nginx:latest":                "library/nginx",
+		"localhost:5000/app":                  "localhost:5000/app",
+		"localhost:5000/app:v2":               "localhost:5000/app",
+		"ghcr.io/org/app@sha256:0123abcd":     "ghcr.io/org/app",
+		"ghcr.io/org/app:v1@sha256:0123abcd":  "ghcr.io/org/app",
+		"registry.example.com:443/team/db:16": "registry.example.com:443/team/db",
+	}
+	for ref, want := range tests {
+		if got := imageRepo(ref); got != want {
+			t.Errorf("imageRepo(%q) = %q, want %q", ref, got, want)
+		}
+	}
+}
@@ -0,0 +1,389 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"path"
+	"strconv"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// filterFields are the fields a structured filter term can name, as in
+// "state:exited".
+var filterFields = []string{"name", "image", "id", "state", "health", "label", "port", "project", "host"}
+
+// filterTerm is one word of a filter query.
+type filterTerm struct {
+	field  string // one of filterFields, empty for a fuzzy term
+	value  string
+	negate bool // the term starts with - or !
+}
+
+// containerFilter is a parsed filter query. A container is shown if it
+// matches every term, negated terms by not matching.
+type containerFilter struct {
+	terms []filterTerm
+}
+
+// parseFilter parses a filter query. Words are split like a shell command,
+// so quotes keep spaces in a value. A word whose part before the colon
+// isn't a field, like "postgres:16", is a fuzzy term.
+func parseFilter(query string) (containerFilter, error) {
+	words, err := splitCommand(query)
+	if err != nil {
+		return containerFilter{}, err
+	}
+	var f containerFilter
+	var errs []error
+	for _, w := range words {
+		t := filterTerm{value: w}
+		if len(w) > 1 && (w[0] == '-' || w[0] == '!') {
+			t.negate, t.value = true, w[1:]
+		}
+		if field, value, ok := strings.Cut(t.value, ":"); ok && isFilterField(field) {
+			t.field, t.value = field, value
+			if err := t.check(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", field, err))
+				continue
+			}
+		}
+		f.terms = append(f.terms, t)
+	}
+	return f, errors.Join(errs...)
+}
+
+func isFilterField(s string) bool {
+	for _, f := range filterFields {
+		if s == f {
+			return true
+		}
+	}
+	return false
+}
+
+// check reports a structured term's value that can never match.
+func (t filterTerm) check() error {
+	if t.value == "" {
+		return errors.New("needs a value")
+	}
+	switch t.field {
+	case "state", "health", "project", "host":
+		if _, err := path.Match(t.value, ""); err != nil {
+			return err
+		}
+	case "label":
+		if _, glob, _ := strings.Cut(t.value, "="); glob != "" {
+			if _, err := path.Match(glob, ""); err != nil {
+				return err
+			}
+		}
+	case "port":
+		if _, err := strconv.Atoi(t.value); err != nil {
+			return fmt.Errorf("%q is not a port number", t.value)
+		}
+	}
+	return nil
+}
+
+// active reports whether the filter has any terms.
+func (f containerFilter) active() bool {
+	return len(f.terms) > 0
+}
+
+// setsState reports whether the filter picks containers by state, in
+// which case it overrides the Running-only view.
+func (f containerFilter) setsState() bool {
+	for _, t := range f.terms {
+		if t.field == "state" {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether c passes the filter.
+func (f containerFilter) matches(c Container) bool {
+	for _, t := range f.terms {
+		if t.matches(c) == t.negate {
+			return false
+		}
+	}
+	return true
+}
+
+func (t filterTerm) matches(c Container) bool {
+	glob := func(s string) bool {
+		ok, _ := path.Match(strings.ToLower(t.value), strings.ToLower(s))
+		return ok
+	}
+	switch t.field {
+	case "":
+		return fuzzyMatch(c.Names, t.value) != nil || fuzzyMatch(c.Image, t.value) != nil || fuzzyMatch(c.ID, t.value) != nil
+	case "name":
+		return substringMatch(c.Names, t.value) != nil
+	case "image":
+		return substringMatch(c.Image, t.value) != nil
+	case "id":
+		return substringMatch(c.ID, t.value) != nil
+	case "state":
+		return glob(c.State)
+	case "health":
+		if c.Health == "" {
+			return glob("none")
+		}
+		return glob(c.Health)
+	case "project":
+		return glob(c.Project)
+	case "host":
+		return glob(c.Host)
+	case "label":
+		return matchContainer(c, "", t.value)
+	case "port":
+		return hasPort(c.Ports, t.value)
+	}
+	return false
+}
+
+// hasPort reports whether a host or container port in the listed ports
+// ("8080->80/tcp, 5432/tcp") is port.
+func hasPort(ports, port string) bool {
+	for _, part := range strings.Split(ports, ", ") {
+		for _, p := range strings.Split(part, "->") {
+			p, _, _ = strings.Cut(p, "/")
+			if p == port {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// substringMatch returns the rune positions of the first case-insensitive
+// occurrence of pattern in text, or nil.
+func substringMatch(text, pattern string) []int {
+	t, p := []rune(strings.ToLower(text)), []rune(strings.ToLower(pattern))
+	if len(p) == 0 {
+		return nil
+	}
+	for i := 0; i+len(p) <= len(t); i++ {
+		if string(t[i:i+len(p)]) == string(p) {
+			pos := make([]int, len(p))
+			for j := range pos {
+				pos[j] = i + j
+			}
+			return pos
+		}
+	}
+	return nil
+}
+
+// fuzzyMatch returns the rune positions where pattern's characters appear
+// in text in order, preferring them side by side, or nil if they don't
+// all appear. Case is ignored.
+func fuzzyMatch(text, pattern string) []int {
+	if pos := substringMatch(text, pattern); pos != nil {
+		return pos
+	}
+	p := []rune(strings.ToLower(pattern))
+	var pos []int
+	for i, r := range []rune(text) {
+		if len(pos) < len(p) && unicode.ToLower(r) == p[len(pos)] {
+			pos = append(pos, i)
+		}
+	}
+	if len(p) == 0 || len(pos) < len(p) {
+		return nil
+	}
+	return pos
+}
+
+// filterHighlights are the rune positions the filter matched in a
+// container's name, image and ID.
+type filterHighlights struct {
+	name, image, id map[int]bool
+}
+
+// highlights works out what to highlight in c's row. Negated terms match
+// by not matching, so they have nothing to show.
+func (f containerFilter) highlights(c Container) filterHighlights {
+	h := filterHighlights{make(map[int]bool), make(map[int]bool), make(map[int]bool)}
+	mark := func(into map[int]bool, pos []int) {
+		for _, i := range pos {
+			into[i] = true
+		}
+	}
+	for _, t := range f.terms {
+		if t.negate {
+			continue
+		}
+		switch t.field {
+		case "":
+			mark(h.name, fuzzyMatch(c.Names, t.value))
+			mark(h.image, fuzzyMatch(c.Image, t.value))
+			mark(h.id, fuzzyMatch(c.ID, t.value))
+		case "name":
+			mark(h.name, substringMatch(c.Names, t.value))
+		case "image":
+			mark(h.image, substringMatch(c.Image, t.value))
+		case "id":
+			mark(h.id, substringMatch(c.ID, t.value))
+		}
+	}
+	return h
+}
+
+// highlightText renders shown, which is raw as it fits in its column,
+// with the matched runes picked out and the rest in base. Only the part
+// still the same as raw is highlighted: a truncated name ends in "...".
+func highlightText(shown, raw string, matched map[int]bool, base lipgloss.Style) string {
+	if len(matched) == 0 {
+		return base.Render(shown)
+	}
+	s, r := []rune(shown), []rune(raw)
+	var b, run strings.Builder
+	runMatched := false
+	flush := func() {
+		if run.Len() == 0 {
+			return
+		}
+		if runMatched {
+			b.WriteString(filterMatchStyle.Render(run.String()))
+		} else {
+			b.WriteString(base.Render(run.String()))
+		}
+		run.Reset()
+	}
+	same := true
+	for i, c := range s {
+		same = same && i < len(r) && r[i] == c
+		hit := same && matched[i]
+		if hit != runMatched {
+			flush()
+			runMatched = hit
+		}
+		run.WriteRune(c)
+	}
+	flush()
+	return b.String()
+}
+
+// loadSavedFilters checks the named filters from the config file. All
+// problems are reported together.
+func loadSavedFilters(user map[string]string) (map[string]string, error) {
+	saved := make(map[string]string, len(user))
+	var errs []error
+	for _, name := range sortedKeys(user) {
+		if _, err := parseFilter(user[name]); err != nil {
+			errs = append(errs, fmt.Errorf("filters[%q]: %w", name, err))
+			continue
+		}
+		saved[name] = user[name]
+	}
+	return saved, errors.Join(errs...)
+}
+
+// ── Filter bar ─────────────────────────────────────────────────────────
+
+// setFilter applies a query. An invalid query keeps the last valid one in
+// effect and shows why in the bar.
+func (m *model) setFilter(query string) {
+	m.filterQuery = query
+	f, err := parseFilter(query)
+	m.filterErr = err
+	if err == nil {
+		m.filter = f
+		m.refilter()
+	}
+}
+
+// filterBarShown reports whether the filter bar takes a line above the
+// footer.
+func (m model) filterBarShown() bool {
+	return m.filterMode || m.filterQuery != ""
+}
+
+// updateFilterBar handles a key while the filter bar is being edited. Like
+// the log filter its keys are fixed: Enter, Esc or / finish editing and
+// leave the filter on, Ctrl+U clears it, Ctrl+S saves it under a name.
+func (m *model) updateFilterBar(msg tea.KeyMsg) tea.Cmd {
+	q := m.filterQuery
+	switch msg.Type {
+	case tea.KeyEnter, tea.KeyEsc:
+		m.filterMode = false
+		return nil
+	case tea.KeyCtrlS:
+		if q == "" || m.filterErr != nil {
+			return nil
+		}
+		m.openPrompt("Save filter as", "", func(m *model, name string) tea.Cmd {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				return nil
+			}
+			m.savedFilters[name] = q
+			return saveConfig(m.configPath, "filters", maps.Clone(m.savedFilters), "Saved the filter "+name)
+		})
+		return nil
+	case tea.KeyBackspace:
+		if r := []rune(q); len(r) > 0 {
+			q = string(r[:len(r)-1])
+		}
+	case tea.KeyCtrlU:
+		q = ""
+	case tea.KeySpace:
+		q += " "
+	case tea.KeyRunes:
+		if string(msg.Runes) == "/" {
+			m.filterMode = false
+			return nil
+		}
+		q += string(msg.Runes)
+	default:
+		return nil
+	}
+	m.setFilter(q)
+	return nil
+}
+
+// openSavedFilters shows the saved filters in a picker.
+func (m *model) openSavedFilters() {
+	if len(m.savedFilters) == 0 {
+		m.statusMsg = "No saved filters; Ctrl+S in the filter bar saves one"
+		m.statusTick = 3
+		return
+	}
+	var options []pickerOption
+	if m.filterQuery != "" {
+		options = append(options, pickerOption{"(none)", "clear the filter"})
+	}
+	for _, name := range sortedKeys(m.savedFilters) {
+		options = append(options, pickerOption{name, m.savedFilters[name]})
+	}
+	m.openPicker("Saved filters", options, func(m *model, name string) tea.Cmd {
+		m.setFilter(m.savedFilters[name])
+		return nil
+	})
+}
+
+// renderFilterBar draws the filter bar with the number of matches.
+func (m model) renderFilterBar() string {
+	bar := "Filter: " + m.filterQuery
+	if m.filterMode {
+		bar += "█"
+	}
+	bar = infoStyle.Render(bar) + "  " + mutedStyle.Render(fmt.Sprintf("%d shown", len(m.filteredContainers)))
+	if m.filterErr != nil {
+		bar += "  " + alertStyle.Render("✖ "+strings.ReplaceAll(m.filterErr.Error(), "\n", "; "))
+	}
+	if m.filterMode {
+		bar += "  " + mutedStyle.Render("(Enter: Done • Ctrl+U: Clear • Ctrl+S: Save)")
+	} else {
+		bar += "  " + mutedStyle.Render(fmt.Sprintf("(%s to edit)", m.keys.keysFor("containers", "filter")))
+	}
+	return bar
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		query    string
+		want     []filterTerm
+		wantErrs []string
+	}{
+		{"", nil, nil},
+		{"web", []filterTerm{{value: "web"}}, nil},
+		{"state:exited -name:db", []filterTerm{{field: "state", value: "exited"}, {field: "name", value: "db", negate: true}}, nil},
+		{"!health:healthy", []filterTerm{{field: "health", value: "healthy", negate: true}}, nil},
+		{"postgres:16", []filterTerm{{value: "postgres:16"}}, nil},
+		{"-", []filterTerm{{value: "-"}}, nil},
+		{`"label:com.example.team=core dev"`, []filterTerm{{field: "label", value: "com.example.team=core dev"}}, nil},
+		{"port:8080 label:tier", []filterTerm{{field: "port", value: "8080"}, {field: "label", value: "tier"}}, nil},
+		{
+			"state: port:http health:[ label:k=[ web",
+			[]filterTerm{{value: "web"}},
+			[]string{"state: needs a value", `port: "http" is not a port number`, "health: syntax error", "label: syntax error"},
+		},
+		{`name:"web`, nil, []string{"unterminated \" quote"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			f, err := parseFilter(tt.query)
+			checkErrs(t, err, tt.wantErrs)
+			if !reflect.DeepEqual(f.terms, tt.want) {
+				t.Errorf("terms = %+v, want %+v", f.terms, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterMatches(t *testing.T) {
+	c := Container{
+		ID:      "3f2a9c1b7d4e",
+		Names:   "shop-db-1",
+		Image:   "postgres:16",
+		State:   "running",
+		Ports:   "5433->5432/tcp",
+		Project: "shop",
+		Labels:  map[string]string{"tier": "backend"},
+	}
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"", true},
+		{"sdb", true},
+		{"PG16", true},
+		{"mysql", false},
+		{"post", true},
+		{"3f2a", true},
+		{"name:db", true},
+		{"name:sdb", false},
+		{"-name:db", false},
+		{"state:run*", true},
+		{"state:exited", false},
+		{"!state:exited", true},
+		{"health:none", true},
+		{"project:SHOP", true},
+		{"label:tier=back*", true},
+		{"label:env", false},
+		{"port:5432", true},
+		{"port:5433", true},
+		{"port:80", false},
+		{"shop state:running port:5432", true},
+		{"shop state:running port:80", false},
+	}
+	for _, tt := range tests {
+		f, err := parseFilter(tt.query)
+		if err != nil {
+			t.Fatalf("parseFilter(%q): %v", tt.query, err)
+		}
+		if got := f.matches(c); got != tt.want {
+			t.Errorf("%q matches = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		text, pattern string
+		want          []int
+	}{
+		{"shop-db-1", "db", []int{5, 6}},
+		{"shop-db-1", "DB", []int{5, 6}},
+		{"shop-db-1", "sd1", []int{0, 5, 8}},
+		{"shop-db-1", "1s", nil},
+		{"shop-db-1", "", nil},
+		{"", "a", nil},
+		{"café-db", "éd", []int{3, 5}},
+	}
+	for _, tt := range tests {
+		if got := fuzzyMatch(tt.text, tt.pattern); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, want %v", tt.text, tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestHasPort(t *testing.T) {
+	tests := []struct {
+		ports, port string
+		want        bool
+	}{
+		{"8080->80/tcp, 5432/tcp", "8080", true},
+		{"8080->80/tcp, 5432/tcp", "80", true},
+		{"8080->80/tcp, 5432/tcp", "5432", true},
+		{"8080->80/tcp, 5432/tcp", "808", false},
+		{"53/udp", "53", true},
+		{"", "80", false},
+	}
+	for _, tt := range tests {
+		if got := hasPort(tt.ports, tt.port); got != tt.want {
+			t.Errorf("hasPort(%q, %q) = %v, want %v", tt.ports, tt.port, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/moby/moby/api/types/container"
+)
+
+// healthRank orders health for sorting: problems first, containers without
+// a healthcheck last.
+func healthRank(h string) int {
+	switch h {
+	case string(container.Unhealthy):
+		return 0
+	case string(container.Starting):
+		return 1
+	case string(container.Healthy):
+		return 2
+	default:
+		return 3
+	}
+}
+
+// renderHealth styles a container's health for the table.
+func renderHealth(h string) string {
+	switch h {
+	case string(container.Healthy):
+		return statusUpStyle.Render("♥ " + h)
+	case string(container.Unhealthy):
+		return statusExitedStyle.Render("✖ " + h)
+	case string(container.Starting):
+		return warnStyle.Render("◌ " + h)
+	default:
+		return mutedStyle.Render("-")
+	}
+}
+
+// healthLines flattens the healthcheck part of an inspect result into the
+// lines of the health log: the check's settings, then the probes the daemon
+// keeps (the last five), newest first.
+func healthLines(info container.InspectResponse) []string {
+	var lines []string
+	kv := func(key, val string) {
+		lines = append(lines, "  "+inspectKeyStyle.Render(fmt.Sprintf("%-16s", key))+" "+val)
+	}
+
+	lines = append(lines, inspectSectionStyle.Render("▌Check"))
+	var hc *container.HealthConfig
+	if info.Config != nil {
+		hc = info.Config.Healthcheck
+	}
+	if hc == nil || len(hc.Test) == 0 || hc.Test[0] == "NONE" {
+		lines = append(lines, "  "+inspectDimStyle.Render("(no healthcheck)"))
+	} else {
+		test := hc.Test
+		if test[0] == "CMD" || test[0] == "CMD-SHELL" {
+			test = test[1:]
+		}
+		kv("Test", strings.Join(test, " "))
+		orDefault := func(d, def time.Duration) string {
+			if d == 0 {
+				d = def
+			}
+			return d.String()
+		}
+		// Zero means the daemon default
+		kv("Interval", orDefault(hc.Interval, 30*time.Second))
+		kv("Timeout", orDefault(hc.Timeout, 30*time.Second))
+		retries := hc.Retries
+		if retries == 0 {
+			retries = 3
+		}
+		kv("Retries", fmt.Sprintf("%d", retries))
+		if hc.StartPeriod > 0 {
+			kv("Start period", hc.StartPeriod.String())
+		}
+	}
+
+	lines = append(lines, "", inspectSectionStyle.Render("▌Status"))
+	if info.State == nil || info.State.Health == nil {
+		kv("Status", "none")
+		return lines
+	}
+	h := info.State.Health
+	kv("Status", renderHealth(string(h.Status)))
+	kv("Failing streak", fmt.Sprintf("%d", h.FailingStreak))
+
+	lines = append(lines, "", inspectSectionStyle.Render("▌Probes"))
+	if len(h.Log) == 0 {
+		lines = append(lines, "  "+inspectDimStyle.Render("(none yet)"))
+	}
+	for i := len(h.Log) - 1; i >= 0; i-- {
+		p := h.Log[i]
+		if p == nil {
+			continue
+		}
+		exit := statusUpStyle.Render(fmt.Sprintf("exit %d", p.ExitCode))
+		if p.ExitCode != 0 {
+			exit = statusExitedStyle.Render(fmt.Sprintf("exit %d", p.ExitCode))
+		}
+		took := "running"
+		if !p.End.IsZero() {
+			took = "took " + p.End.Sub(p.Start).Round(time.Millisecond).String()
+		}
+		lines = append(lines, "  "+inspectKeyStyle.Render(p.Start.Local().Format("2006-01-02 15:04:05"))+"  "+exit+"  "+inspectDimStyle.Render(took))
+		out := strings.TrimSpace(p.Output)
+		if out == "" {
+			lines = append(lines, "    "+inspectDimStyle.Render("(no output)"))
+		}
+		for _, l := range strings.Split(out, "\n") {
+			if l != "" {
+				lines = append(lines, "    "+strings.TrimRight(l, "\r"))
+			}
+		}
+	}
+	return lines
+}
+
+func (m model) healthBodyHeight() int {
+	// title + blank line, footer with its top margin
+	h := m.height - 2 - 2
+	if h < 1 {
+		h = 1
+	}
+	return h
+}
+
+// maxHealthOffset keeps the last line at the bottom of the pane.
+func (m model) maxHealthOffset() int {
+	return max(len(m.healthLines)-m.healthBodyHeight(), 0)
+}
+
+// scrollHealth moves the health log viewport by delta lines.
+func (m *model) scrollHealth(delta int) {
+	m.healthOffset = max(min(m.healthOffset+delta, m.maxHealthOffset()), 0)
+}
+
+// renderHealthView renders the full-screen health log.
+func (m model) renderHealthView() string {
+	title := titleStyle.Render(fmt.Sprintf("Health: %s", m.healthName))
+	if m.healthErr != nil {
+		title += "  " + statusExitedStyle.Render("✖ "+m.healthErr.Error())
+	}
+
+	footer := helpStyle.Render(m.keys.footer("health"))
+
+	bodyH := m.healthBodyHeight()
+	lines := m.healthLines
+	if lines == nil {
+		lines = []string{"Loading..."}
+	}
+	offset := max(min(m.healthOffset, len(lines)-bodyH), 0)
+	end := min(offset+bodyH, len(lines))
+	visible := append([]string(nil), lines[offset:end]...)
+	for len(visible) < bodyH {
+		visible = append(visible, "")
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, "", strings.Join(visible, "\n"), footer)
+}
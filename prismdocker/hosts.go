@@ -0,0 +1,234 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/moby/moby/client"
+)
+
+// daemonHost is one of the daemons of the hosts view.
+type daemonHost struct {
+	name       string
+	cli        *client.Client // nil if it couldn't be created; err says why
+	containers []Container    // last listing, each with Host set
+	err        error          // last connection error; the host's rows are stale while set
+	pending    bool           // a listing has been asked for and not yet arrived
+}
+
+// hostConfig is a daemon the hosts view connects to, as in the config file.
+type hostConfig struct {
+	name     string
+	endpoint dockerEndpoint
+}
+
+// loadHosts checks the hosts from the config file and returns them by
+// name. All problems are reported together.
+func loadHosts(user map[string]dockerEndpoint) ([]hostConfig, error) {
+	var hosts []hostConfig
+	var errs []error
+	for _, name := range sortedKeys(user) {
+		e := user[name]
+		problems := e.check()
+		for _, err := range problems {
+			errs = append(errs, fmt.Errorf("hosts[%q]: %w", name, err))
+		}
+		if len(problems) == 0 {
+			hosts = append(hosts, hostConfig{name, e})
+		}
+	}
+	return hosts, errors.Join(errs...)
+}
+
+// check returns everything wrong with an endpoint from the config file.
+func (e dockerEndpoint) check() []error {
+	var errs []error
+	switch {
+	case e.Host == "":
+		errs = append(errs, errors.New("host: needs a tcp:// or ssh:// URL"))
+	case strings.HasPrefix(e.Host, "ssh://"):
+		if u, err := url.Parse(e.Host); err != nil || u.Hostname() == "" {
+			errs = append(errs, fmt.Errorf("host: %q is not an ssh://[user@]host[:port] URL", e.Host))
+		}
+		if e.CA != "" || e.Cert != "" || e.Key != "" || e.SkipTLSVerify {
+			errs = append(errs, errors.New("TLS settings don't apply to ssh:// hosts"))
+		}
+	default:
+		if _, err := client.ParseHostURL(e.Host); err != nil {
+			errs = append(errs, fmt.Errorf("host: %w", err))
+		}
+	}
+	if (e.Cert == "") != (e.Key == "") {
+		errs = append(errs, errors.New("cert and key go together"))
+	}
+	for _, f := range []string{e.CA, e.Cert, e.Key} {
+		if f == "" {
+			continue
+		}
+		if _, err := os.Stat(f); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// hostname is the machine an endpoint's published ports are on.
+func (e dockerEndpoint) hostname() string {
+	if u, err := url.Parse(e.Host); err == nil && (u.Scheme == "tcp" || u.Scheme == "ssh") && u.Hostname() != "" {
+		return u.Hostname()
+	}
+	return "localhost"
+}
+
+// portHost is the machine c's published ports are on: its host's in the
+// hosts view, the current context's otherwise.
+func (m model) portHost(c Container) string {
+	for _, hc := range m.hostConfigs {
+		if hc.name == c.Host {
+			return hc.endpoint.hostname()
+		}
+	}
+	if i := findContext(m.contexts, m.contextName); i >= 0 {
+		return dockerEndpoint{Host: m.contexts[i].host()}.hostname()
+	}
+	return "localhost"
+}
+
+// ── Hosts view ─────────────────────────────────────────────────────────
+
+// clientFor returns the client of the daemon c runs on.
+func (m model) clientFor(c Container) *client.Client {
+	return m.clientOn(c.Host)
+}
+
+// clientOn returns the client of the named host, or the current context's
+// for containers outside the hosts view, whose host is "".
+func (m model) clientOn(host string) *client.Client {
+	if host != "" {
+		for _, h := range m.hosts {
+			if h.name == host {
+				return h.cli
+			}
+		}
+	}
+	return m.dockerClient
+}
+
+// findHost returns the host whose client is cli, or nil.
+func (m model) findHost(cli *client.Client) *daemonHost {
+	for _, h := range m.hosts {
+		if h.cli == cli {
+			return h
+		}
+	}
+	return nil
+}
+
+// hostErr is the last connection error of the named host, nil if it
+// answered or there is no such host.
+func (m model) hostErr(name string) error {
+	for _, h := range m.hosts {
+		if h.name == name {
+			return h.err
+		}
+	}
+	return nil
+}
+
+// hostsUp counts the hosts that answered their last listing.
+func (m model) hostsUp() int {
+	n := 0
+	for _, h := range m.hosts {
+		if h.err == nil {
+			n++
+		}
+	}
+	return n
+}
+
+// refreshContainers lists the containers again: of every host in the hosts
+// view, skipping those still busy with the last listing, or of the
+// current context.
+func (m *model) refreshContainers() tea.Cmd {
+	if m.hosts == nil {
+		if m.dockerClient == nil {
+			return nil
+		}
+		return fetchContainers(m.dockerClient)
+	}
+	var cmds []tea.Cmd
+	for _, h := range m.hosts {
+		if h.cli != nil && !h.pending {
+			h.pending = true
+			cmds = append(cmds, fetchContainers(h.cli))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// setHostContainers takes in a host's listing. The merged list keeps the
+// hosts in config order.
+func (m *model) setHostContainers(h *daemonHost, containers []Container) {
+	h.pending, h.err = false, nil
+	for i := range containers {
+		containers[i].Host = h.name
+	}
+	h.containers = containers
+	var all []Container
+	for _, other := range m.hosts {
+		all = append(all, other.containers...)
+	}
+	m.allContainers = all
+	m.refilter()
+}
+
+// openHosts connects to every configured host. A client that can't be
+// created leaves its host unreachable, with the reason.
+func (m *model) openHosts() {
+	m.hosts = nil
+	for _, hc := range m.hostConfigs {
+		h := &daemonHost{name: hc.name}
+		h.cli, h.err = newEndpointClient(hc.endpoint)
+		m.hosts = append(m.hosts, h)
+	}
+}
+
+// toggleHosts switches between the hosts view and the current context.
+func (m *model) toggleHosts() tea.Cmd {
+	if m.hosts != nil {
+		m.dropDaemonState()
+		m.statusMsg = "Back to context " + m.contextName
+		m.statusTick = 3
+		if m.dockerClient == nil {
+			return nil
+		}
+		return tea.Batch(fetchContainers(m.dockerClient), subscribeEvents(m.dockerClient, m.eventsSession))
+	}
+	if len(m.hostConfigs) == 0 {
+		m.statusMsg = "No hosts in the config file"
+		m.statusTick = 3
+		return nil
+	}
+	m.dropDaemonState()
+	m.openHosts()
+	m.statusMsg = fmt.Sprintf("Connecting to %d hosts...", len(m.hosts))
+	m.statusTick = 3
+	return m.refreshContainers()
+}
+
+// closeHosts closes the clients of the hosts view and leaves it.
+func (m *model) closeHosts() {
+	for _, h := range m.hosts {
+		if h.cli != nil {
+			h.cli.Close()
+		}
+	}
+	m.hosts = nil
+	if m.sortOrder == SortByHost {
+		m.sortOrder = SortByState
+	}
+}
@@ -0,0 +1,528 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/moby/moby/api/types/jsonstream"
+	"github.com/moby/moby/client"
+)
+
+// Image is one row of the images table. An image with several tags shows
+// up once per tag, like `docker images` does.
+type Image struct {
+	ID         string // full "sha256:..." ID
+	Repository string
+	Tag        string
+	Size       int64
+	Created    time.Time
+	Dangling   bool
+}
+
+// Ref is what the image should be addressed by: repo:tag when tagged,
+// otherwise the ID.
+func (i Image) Ref() string {
+	if i.Dangling {
+		return i.ShortID()
+	}
+	return i.Repository + ":" + i.Tag
+}
+
+func (i Image) ShortID() string {
+	id := strings.TrimPrefix(i.ID, "sha256:")
+	if len(id) > 12 {
+		id = id[:12]
+	}
+	return id
+}
+
+type ImageSortOrder int
+
+const (
+	ImageSortByRepository ImageSortOrder = iota
+	ImageSortBySize
+	ImageSortByCreated
+	ImageSortByContainers
+)
+
+func (s ImageSortOrder) String() string {
+	switch s {
+	case ImageSortByRepository:
+		return "Repository"
+	case ImageSortBySize:
+		return "Size"
+	case ImageSortByCreated:
+		return "Created"
+	case ImageSortByContainers:
+		return "Containers"
+	default:
+		return "Unknown"
+	}
+}
+
+func ListImages(cli *client.Client) ([]Image, error) {
+	res, err := cli.ImageList(context.Background(), client.ImageListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var result []Image
+	for _, img := range res.Items {
+		base := Image{
+			ID:      img.ID,
+			Size:    img.Size,
+			Created: time.Unix(img.Created, 0),
+		}
+		tags := img.RepoTags
+		if len(tags) == 0 {
+			tags = []string{"<none>:<none>"}
+		}
+		for _, t := range tags {
+			i := base
+			// Split on the last colon so registry ports stay in the repo
+			if idx := strings.LastIndex(t, ":"); idx > 0 && !strings.Contains(t[idx:], "/") {
+				i.Repository, i.Tag = t[:idx], t[idx+1:]
+			} else {
+				i.Repository, i.Tag = t, "<none>"
+			}
+			i.Dangling = i.Repository == "<none>"
+			result = append(result, i)
+		}
+	}
+	return result, nil
+}
+
+func RemoveImage(cli *client.Client, ref string) error {
+	_, err := cli.ImageRemove(context.Background(), ref, client.ImageRemoveOptions{PruneChildren: true})
+	return err
+}
+
+func TagImage(cli *client.Client, source, target string) error {
+	_, err := cli.ImageTag(context.Background(), client.ImageTagOptions{Source: source, Target: target})
+	return err
+}
+
+// DanglingImages lists untagged images, used to preview what a prune removes.
+func DanglingImages(cli *client.Client) (count int, size int64, err error) {
+	res, err := cli.ImageList(context.Background(), client.ImageListOptions{
+		Filters: make(client.Filters).Add("dangling", "true"),
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, img := range res.Items {
+		size += img.Size
+	}
+	return len(res.Items), size, nil
+}
+
+func PruneDanglingImages(cli *client.Client) (count int, reclaimed uint64, err error) {
+	res, err := cli.ImagePrune(context.Background(), client.ImagePruneOptions{
+		Filters: make(client.Filters).Add("dangling", "true"),
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return len(res.Report.ImagesDeleted), res.Report.SpaceReclaimed, nil
+}
+
+// imageUsage counts containers (running or not) per image ID.
+func imageUsage(containers []Container) map[string]int {
+	usage := make(map[string]int)
+	for _, c := range containers {
+		usage[c.ImageID]++
+	}
+	return usage
+}
+
+func sortImages(images []Image, order ImageSortOrder, usage map[string]int) {
+	sort.SliceStable(images, func(i, j int) bool {
+		a, b := images[i], images[j]
+		switch order {
+		case ImageSortBySize:
+			return a.Size > b.Size // descending
+		case ImageSortByCreated:
+			return a.Created.After(b.Created) // newest first
+		case ImageSortByContainers:
+			if usage[a.ID] != usage[b.ID] {
+				return usage[a.ID] > usage[b.ID] // descending
+			}
+		}
+		// Dangling images sink to the bottom
+		if a.Dangling != b.Dangling {
+			return !a.Dangling
+		}
+		if a.Repository != b.Repository {
+			return a.Repository < b.Repository
+		}
+		return a.Tag < b.Tag
+	})
+}
+
+type imagesMsg struct {
+	cli    *client.Client
+	images []Image
+	err    error
+}
+
+// imageActionMsg reports the result of an image action for the footer.
+type imageActionMsg struct {
+	cli  *client.Client
+	done string
+	err  error
+}
+
+// imagePrunePreviewMsg carries what an image prune would remove.
+type imagePrunePreviewMsg struct {
+	cli   *client.Client
+	count int
+	size  int64
+	err   error
+}
+
+func fetchImages(cli *client.Client) tea.Cmd {
+	return func() tea.Msg {
+		images, err := ListImages(cli)
+		return imagesMsg{cli, images, err}
+	}
+}
+
+func doImageAction(cli *client.Client, done string, fn func(*client.Client) error) tea.Cmd {
+	return func() tea.Msg {
+		return imageActionMsg{cli, done, fn(cli)}
+	}
+}
+
+func previewImagePrune(cli *client.Client) tea.Cmd {
+	return func() tea.Msg {
+		count, size, err := DanglingImages(cli)
+		return imagePrunePreviewMsg{cli, count, size, err}
+	}
+}
+
+func pruneImages(cli *client.Client) tea.Cmd {
+	return func() tea.Msg {
+		count, reclaimed, err := PruneDanglingImages(cli)
+		return imageActionMsg{cli, fmt.Sprintf("Pruned %d image(s), reclaimed %s.", count, formatBytes(float64(reclaimed))), err}
+	}
+}
+
+// ── Pull progress ──────────────────────────────────────────────────────
+
+// pullLayer is the latest progress reported for one layer of a pull.
+type pullLayer struct {
+	status  string
+	current int64
+	total   int64
+}
+
+// imagePull is a running `docker pull` with per-layer progress.
+type imagePull struct {
+	ref      string
+	session  int
+	messages chan jsonstream.Message
+	cancel   context.CancelFunc
+	order    []string // layer IDs in the order they first appeared
+	layers   map[string]*pullLayer
+	status   string // latest message not tied to a layer
+}
+
+// pullProgressMsg carries a batch of progress messages from a pull.
+type pullProgressMsg struct {
+	session  int
+	messages []jsonstream.Message
+}
+
+// pullDoneMsg is sent when a pull finishes.
+type pullDoneMsg struct {
+	cli     *client.Client
+	session int
+	err     error
+}
+
+func newImagePull(ref string, session int) *imagePull {
+	return &imagePull{
+		ref:      ref,
+		session:  session,
+		messages: make(chan jsonstream.Message, 64),
+		layers:   make(map[string]*pullLayer),
+	}
+}
+
+// runPull performs the pull and forwards every progress message.
+func runPull(cli *client.Client, p *imagePull) tea.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	return func() tea.Msg {
+		defer close(p.messages)
+		resp, err := cli.ImagePull(ctx, p.ref, client.ImagePullOptions{})
+		if err != nil {
+			return pullDoneMsg{cli, p.session, err}
+		}
+		defer resp.Close()
+		for msg, err := range resp.JSONMessages(ctx) {
+			if err != nil {
+				return pullDoneMsg{cli, p.session, err}
+			}
+			if msg.Error != nil {
+				return pullDoneMsg{cli, p.session, msg.Error}
+			}
+			select {
+			case p.messages <- msg:
+			case <-ctx.Done():
+				return pullDoneMsg{cli, p.session, ctx.Err()}
+			}
+		}
+		return pullDoneMsg{cli, p.session, nil}
+	}
+}
+
+// cancelPull abandons the running pull, if any. Its context ends the
+// request; whatever it still sends is dropped by session.
+func (m *model) cancelPull() {
+	if m.pull != nil {
+		m.pull.cancel()
+		m.pull = nil
+	}
+}
+
+func waitForPullProgress(p *imagePull) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-p.messages
+		if !ok {
+			return nil
+		}
+		msgs := []jsonstream.Message{msg}
+		for len(msgs) < 64 {
+			select {
+			case msg, ok := <-p.messages:
+				if !ok {
+					return pullProgressMsg{p.session, msgs}
+				}
+				msgs = append(msgs, msg)
+			default:
+				return pullProgressMsg{p.session, msgs}
+			}
+		}
+		return pullProgressMsg{p.session, msgs}
+	}
+}
+
+// apply folds a progress message into the per-layer state.
+func (p *imagePull) apply(msg jsonstream.Message) {
+	if !isLayerID(msg.ID) {
+		if msg.Status != "" {
+			p.status = msg.Status
+		}
+		return
+	}
+	l, ok := p.layers[msg.ID]
+	if !ok {
+		l = &pullLayer{}
+		p.layers[msg.ID] = l
+		p.order = append(p.order, msg.ID)
+	}
+	l.status = msg.Status
+	if msg.Progress != nil && msg.Progress.Total > 0 {
+		l.current, l.total = msg.Progress.Current, msg.Progress.Total
+	} else {
+		l.current, l.total = 0, 0
+	}
+}
+
+// isLayerID reports whether a progress message ID is a short layer digest
+// rather than a tag or digest belonging to the whole image.
+func isLayerID(id string) bool {
+	if len(id) != 12 {
+		return false
+	}
+	for _, r := range id {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *imagePull) completedLayers() int {
+	n := 0
+	for _, l := range p.layers {
+		if l.status == "Pull complete" || l.status == "Already exists" {
+			n++
+		}
+	}
+	return n
+}
+
+// renderProgress draws a plain progress bar like [■■■■□□□□].
+func renderProgress(current, total int64, width int) string {
+	filled := 0
+	if total > 0 {
+		filled = int(float64(current) / float64(total) * float64(width))
+	}
+	if filled > width {
+		filled = width
+	}
+	return "[" + strings.Repeat("■", filled) + strings.Repeat("□", width-filled) + "]"
+}
+
+// render draws the pull panel, limited to maxLines lines.
+func (p *imagePull) render(maxLines int) []string {
+	head := titleStyle.Render(fmt.Sprintf("Pulling %s — %d/%d layers", p.ref, p.completedLayers(), len(p.order)))
+	if p.status != "" {
+		head += "  " + imageStyle.Render(p.status)
+	}
+	lines := []string{head}
+	order := p.order
+	if over := len(order) - (maxLines - 1); over > 0 {
+		order = order[over:]
+	}
+	for _, id := range order {
+		l := p.layers[id]
+		line := fmt.Sprintf("  %s  %-18s", idStyle.Render(id), l.status)
+		if l.total > 0 {
+			line += fmt.Sprintf(" %s %s/%s", renderProgress(l.current, l.total, 20),
+				formatBytesShort(float64(l.current)), formatBytesShort(float64(l.total)))
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// ── Images screen ──────────────────────────────────────────────────────
+
+// imagePanelHeight is how many lines the pull panel takes while pulling.
+func (m model) imagePanelHeight() int {
+	if m.pull == nil {
+		return 0
+	}
+	return 8
+}
+
+// imageBodyHeight is the number of table rows that fit on screen.
+func (m model) imageBodyHeight() int {
+	// title + table header with its border, footer with its top margin
+	h := m.height - 1 - 3 - 2 - m.imagePanelHeight()
+	if m.prompt != nil {
+		h--
+	}
+	if h < 1 {
+		h = 1
+	}
+	return h
+}
+
+// moveImageCursor moves the cursor by delta rows and keeps it on screen.
+func (m *model) moveImageCursor(delta int) {
+	m.imageList.move(delta, len(m.images), m.imageBodyHeight())
+}
+
+func (m model) currentImage() (Image, bool) {
+	if m.imageList.cursor < len(m.images) {
+		return m.images[m.imageList.cursor], true
+	}
+	return Image{}, false
+}
+
+// renderImagesView renders the full-screen images table.
+func (m model) renderImagesView() string {
+	usage := imageUsage(m.allContainers)
+	var total int64
+	for _, img := range m.images {
+		total += img.Size
+	}
+
+	title := titleStyle.Render(fmt.Sprintf("Images (%d, %s)", len(m.images), formatBytes(float64(total))))
+	title += "  " + infoStyle.Render("Sort: "+m.imageSort.String())
+	if m.imagesErr != nil {
+		title += "  " + statusExitedStyle.Render("✖ "+m.imagesErr.Error())
+	}
+
+	available := m.width - 10
+	if available < 40 {
+		available = 40
+	}
+	wID, wTag, wSize, wCreated, wUsed := 14, 20, 10, 10, 12
+	wRepo := available - wID - wTag - wSize - wCreated - wUsed
+	if wRepo < 20 {
+		wRepo = 20
+	}
+
+	tHeader := tableStyle.Render(lipgloss.JoinHorizontal(lipgloss.Top,
+		ListItemStyle.Width(2).Render(""),
+		ListItemStyle.Width(wRepo).Render("Repository"),
+		ListItemStyle.Width(wTag).Render("Tag"),
+		ListItemStyle.Width(wID).Render("ID"),
+		ListItemStyle.Width(wSize).Render("Size"),
+		ListItemStyle.Width(wCreated).Render("Created"),
+		ListItemStyle.Width(wUsed).Render("Containers"),
+	))
+
+	bodyH := m.imageBodyHeight()
+	start, end := m.imageList.window(len(m.images), bodyH)
+
+	var rows []string
+	if len(m.images) == 0 {
+		rows = append(rows, "No images found.")
+	}
+	for i := start; i < end; i++ {
+		img := m.images[i]
+		selected := i == m.imageList.cursor
+		style := ListItemStyle
+		if selected {
+			style = selectedStyle
+		} else if i%2 == 0 {
+			style = style.Copy().Background(rowEvenBg) // Zebra stripe
+		}
+		cursor := "  "
+		if selected {
+			cursor = "> "
+		}
+		id := img.ShortID()
+		repo := truncate(img.Repository, wRepo-2)
+		if !selected {
+			id = idStyle.Render(id)
+			if img.Dangling {
+				repo = imageStyle.Render(repo)
+			}
+		}
+		used := "-"
+		if n := usage[img.ID]; n > 0 {
+			used = fmt.Sprintf("%d", n)
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top,
+			style.Render(cursor),
+			style.Width(wRepo).Render(repo),
+			style.Width(wTag).Render(truncate(img.Tag, wTag-2)),
+			style.Width(wID).Render(id),
+			style.Width(wSize).Render(formatBytesShort(float64(img.Size))),
+			style.Width(wCreated).Render(formatAge(time.Since(img.Created))),
+			style.Width(wUsed).Render(used),
+		))
+	}
+	for len(rows) < bodyH {
+		rows = append(rows, "")
+	}
+
+	footerText := m.keys.footer("images")
+	if m.statusMsg != "" {
+		footerText = m.statusMsg
+	}
+	parts := []string{title, tHeader, lipgloss.JoinVertical(lipgloss.Left, rows...)}
+	if m.pull != nil {
+		panel := m.pull.render(m.imagePanelHeight())
+		for len(panel) < m.imagePanelHeight() {
+			panel = append(panel, "")
+		}
+		parts = append(parts, panel...)
+	}
+	if m.prompt != nil {
+		parts = append(parts, m.prompt.render())
+	}
+	parts = append(parts, helpStyle.Render(footerText))
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/moby/moby/client"
+)
+
+func TestLeavingImagesCancelsPull(t *testing.T) {
+	keys, err := newKeyMap(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://127.0.0.1:1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		key      tea.KeyMsg
+		wantView ActiveView
+	}{
+		{"esc cancels and stays", tea.KeyMsg{Type: tea.KeyEsc}, viewImages},
+		{"switching screens", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3")}, viewVolumes},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cancelled := false
+			m := model{dockerClient: cli, keys: keys, activeView: viewImages, pull: newImagePull("nginx:latest", 1)}
+			m.pull.cancel = func() { cancelled = true }
+
+			m = step(t, m, tt.key)
+			if !cancelled || m.pull != nil {
+				t.Errorf("cancelled %v, pull %v; want the pull cancelled and gone", cancelled, m.pull)
+			}
+			if m.activeView != tt.wantView {
+				t.Errorf("view = %d, want %d", m.activeView, tt.wantView)
+			}
+		})
+	}
+
+	// With no pull running, Esc leaves
+	m := step(t, model{keys: keys, activeView: viewImages}, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.activeView != viewContainers {
+		t.Errorf("view = %d, want the container list", m.activeView)
+	}
+}
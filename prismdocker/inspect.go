@@ -0,0 +1,313 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+)
+
+// inspectMsg carries a ContainerInspect result for the detail pane.
+type inspectMsg struct {
+	id   string
+	info container.InspectResponse
+	err  error
+}
+
+func fetchInspect(cli *client.Client, containerID string) tea.Cmd {
+	return func() tea.Msg {
+		res, err := cli.ContainerInspect(context.Background(), containerID, client.ContainerInspectOptions{})
+		return inspectMsg{id: containerID, info: res.Container, err: err}
+	}
+}
+
+// inspectLines flattens an inspect result into the lines shown in the pane.
+func inspectLines(info container.InspectResponse) []string {
+	var lines []string
+	section := func(title string) {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, inspectSectionStyle.Render("▌"+title))
+	}
+	kv := func(key, val string) {
+		if val == "" {
+			val = inspectDimStyle.Render("-")
+		}
+		lines = append(lines, "  "+inspectKeyStyle.Render(fmt.Sprintf("%-16s", key))+" "+val)
+	}
+	item := func(s string) {
+		lines = append(lines, "  "+s)
+	}
+	none := func() {
+		item(inspectDimStyle.Render("(none)"))
+	}
+
+	cfg := info.Config
+	if cfg == nil {
+		cfg = &container.Config{}
+	}
+	host := info.HostConfig
+	if host == nil {
+		host = &container.HostConfig{}
+	}
+
+	// Overview
+	section("Overview")
+	kv("ID", info.ID)
+	kv("Name", strings.TrimPrefix(info.Name, "/"))
+	kv("Image", cfg.Image)
+	kv("Created", formatInspectTime(info.Created))
+	if st := info.State; st != nil {
+		kv("State", string(st.Status))
+		if st.Running {
+			kv("Started", formatInspectTime(st.StartedAt))
+		} else {
+			kv("Finished", formatInspectTime(st.FinishedAt))
+			kv("Exit code", fmt.Sprintf("%d", st.ExitCode))
+		}
+		if st.OOMKilled {
+			kv("OOM killed", statusExitedStyle.Render("yes"))
+		}
+		if st.Error != "" {
+			kv("Error", statusExitedStyle.Render(st.Error))
+		}
+	}
+	kv("Restart count", fmt.Sprintf("%d", info.RestartCount))
+
+	// Command
+	section("Command")
+	kv("Entrypoint", strings.Join(cfg.Entrypoint, " "))
+	kv("Cmd", strings.Join(cfg.Cmd, " "))
+	kv("Working dir", cfg.WorkingDir)
+	kv("User", cfg.User)
+
+	// Environment
+	section("Environment")
+	if len(cfg.Env) == 0 {
+		none()
+	}
+	for _, e := range cfg.Env {
+		if k, v, ok := strings.Cut(e, "="); ok {
+			item(inspectKeyStyle.Render(k) + "=" + v)
+		} else {
+			item(e)
+		}
+	}
+
+	// Mounts
+	section("Mounts")
+	if len(info.Mounts) == 0 {
+		none()
+	}
+	for _, mnt := range info.Mounts {
+		src := mnt.Source
+		if mnt.Name != "" {
+			src = mnt.Name
+		}
+		mode := "ro"
+		if mnt.RW {
+			mode = "rw"
+		}
+		item(fmt.Sprintf("%s %s -> %s %s", inspectDimStyle.Render(string(mnt.Type)), src, mnt.Destination, inspectDimStyle.Render(mode)))
+	}
+
+	// Networks
+	section("Networks")
+	var netNames []string
+	if info.NetworkSettings != nil {
+		for name := range info.NetworkSettings.Networks {
+			netNames = append(netNames, name)
+		}
+	}
+	sort.Strings(netNames)
+	if len(netNames) == 0 {
+		none()
+	}
+	for _, name := range netNames {
+		ep := info.NetworkSettings.Networks[name]
+		if ep == nil {
+			item(name)
+			continue
+		}
+		ip := "-"
+		if ep.IPAddress.IsValid() {
+			ip = fmt.Sprintf("%s/%d", ep.IPAddress, ep.IPPrefixLen)
+		}
+		line := fmt.Sprintf("%s  ip %s", inspectKeyStyle.Render(name), ip)
+		if ep.Gateway.IsValid() {
+			line += fmt.Sprintf("  gw %s", ep.Gateway)
+		}
+		if mac := ep.MacAddress.String(); mac != "" {
+			line += "  mac " + mac
+		}
+		item(line)
+	}
+
+	// Restart policy
+	section("Restart Policy")
+	policy := string(host.RestartPolicy.Name)
+	if policy == "" {
+		policy = "no"
+	}
+	if host.RestartPolicy.MaximumRetryCount > 0 {
+		policy += fmt.Sprintf(" (max %d retries)", host.RestartPolicy.MaximumRetryCount)
+	}
+	kv("Policy", policy)
+
+	// Labels
+	section("Labels")
+	var labelKeys []string
+	for k := range cfg.Labels {
+		labelKeys = append(labelKeys, k)
+	}
+	sort.Strings(labelKeys)
+	if len(labelKeys) == 0 {
+		none()
+	}
+	for _, k := range labelKeys {
+		item(inspectKeyStyle.Render(k) + "=" + cfg.Labels[k])
+	}
+
+	// Health
+	section("Health")
+	if info.State == nil || info.State.Health == nil {
+		kv("Status", "none")
+	} else {
+		h := info.State.Health
+		status := string(h.Status)
+		switch h.Status {
+		case container.Healthy:
+			status = statusUpStyle.Render(status)
+		case container.Unhealthy:
+			status = statusExitedStyle.Render(status)
+		}
+		kv("Status", status)
+		kv("Failing streak", fmt.Sprintf("%d", h.FailingStreak))
+		if n := len(h.Log); n > 0 && h.Log[n-1] != nil && !h.Log[n-1].End.IsZero() {
+			last := h.Log[n-1]
+			kv("Last probe", fmt.Sprintf("%s (exit %d, took %s)",
+				last.End.Local().Format("2006-01-02 15:04:05"), last.ExitCode, last.End.Sub(last.Start).Round(time.Millisecond)))
+			out := strings.TrimSpace(last.Output)
+			if out == "" {
+				item(inspectDimStyle.Render("(no output)"))
+			}
+			for _, l := range strings.Split(out, "\n") {
+				if l != "" {
+					item("  " + l)
+				}
+			}
+		}
+	}
+
+	// Resources
+	section("Resources")
+	r := host.Resources
+	kv("Memory", formatLimitBytes(r.Memory))
+	kv("Memory reserve", formatLimitBytes(r.MemoryReservation))
+	kv("Memory+swap", formatLimitBytes(r.MemorySwap))
+	cpus := "unlimited"
+	if r.NanoCPUs > 0 {
+		cpus = fmt.Sprintf("%.2f", float64(r.NanoCPUs)/1e9)
+	}
+	kv("CPUs", cpus)
+	if r.CPUShares > 0 {
+		kv("CPU shares", fmt.Sprintf("%d", r.CPUShares))
+	}
+	if r.CpusetCpus != "" {
+		kv("CPU set", r.CpusetCpus)
+	}
+	pids := "unlimited"
+	if r.PidsLimit != nil && *r.PidsLimit > 0 {
+		pids = fmt.Sprintf("%d", *r.PidsLimit)
+	}
+	kv("PIDs", pids)
+
+	return lines
+}
+
+// formatInspectTime turns an RFC 3339 timestamp into local time plus age.
+func formatInspectTime(ts string) string {
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil || t.IsZero() || t.Year() < 2 {
+		return ""
+	}
+	return fmt.Sprintf("%s (%s ago)", t.Local().Format("2006-01-02 15:04:05"), formatAge(time.Since(t)))
+}
+
+// formatLimitBytes renders a byte limit where zero means no limit.
+func formatLimitBytes(b int64) string {
+	if b <= 0 {
+		return "unlimited"
+	}
+	return formatBytes(float64(b))
+}
+
+// inspectBodyHeight is the number of lines that fit in the inspect pane.
+func (m model) inspectBodyHeight() int {
+	// title + blank line, footer with its top margin
+	h := m.height - 2 - 2
+	if h < 1 {
+		h = 1
+	}
+	return h
+}
+
+// maxInspectOffset keeps the last line at the bottom of the pane.
+func (m model) maxInspectOffset() int {
+	max := len(m.inspectLines) - m.inspectBodyHeight()
+	if max < 0 {
+		return 0
+	}
+	return max
+}
+
+// scrollInspect moves the inspect viewport by delta lines.
+func (m *model) scrollInspect(delta int) {
+	m.inspectOffset += delta
+	if max := m.maxInspectOffset(); m.inspectOffset > max {
+		m.inspectOffset = max
+	}
+	if m.inspectOffset < 0 {
+		m.inspectOffset = 0
+	}
+}
+
+// renderInspectView renders the full-screen container detail pane.
+func (m model) renderInspectView() string {
+	title := titleStyle.Render(fmt.Sprintf("Inspect: %s", m.inspectName))
+	if m.inspectErr != nil {
+		title += "  " + statusExitedStyle.Render("✖ "+m.inspectErr.Error())
+	}
+
+	footer := helpStyle.Render(m.keys.footer("inspect"))
+
+	bodyH := m.inspectBodyHeight()
+	lines := m.inspectLines
+	if lines == nil {
+		lines = []string{"Loading..."}
+	}
+	offset := m.inspectOffset
+	if max := len(lines) - bodyH; offset > max {
+		offset = max
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	end := offset + bodyH
+	if end > len(lines) {
+		end = len(lines)
+	}
+	visible := append([]string(nil), lines[offset:end]...)
+	for len(visible) < bodyH {
+		visible = append(visible, "")
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, "", strings.Join(visible, "\n"), footer)
+}
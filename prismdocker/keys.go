@@ -0,0 +1,351 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// binding is one named action and the keys that trigger it. Keys use the
+// names Bubble Tea reports for a key press ("x", "ctrl+d", "pgup", ...),
+// except that the space bar is written "space".
+type binding struct {
+	action string
+	keys   []string
+	footer string // label in the footer help; bindings sharing a label are merged
+	help   string // description for the keymap tables
+}
+
+// keySection is the set of bindings active in one view.
+type keySection struct {
+	name     string // key under "keys" in the config file
+	title    string // heading in the keymap tables
+	bindings []binding
+}
+
+// defaultKeySections is the built-in keymap, in the order it is documented.
+func defaultKeySections() []keySection {
+	return []keySection{
+		{"containers", "Container list", []binding{
+			{"up", []string{"up", "k"}, "Nav", "Move cursor up"},
+			{"down", []string{"down", "j"}, "Nav", "Move cursor down"},
+			{"mark", []string{"space"}, "Mark", "Mark / unmark the highlighted row; on a project header, all of its shown containers"},
+			{"mark-all", []string{"V"}, "All/Invert", "Mark every visible row"},
+			{"invert-marks", []string{"v"}, "All/Invert", "Invert the marks on visible rows"},
+			{"clear-marks", []string{"esc"}, "", "Clear all marks"},
+			{"refresh", []string{"r"}, "Refresh", "Manual full refresh"},
+			{"sort", []string{"s"}, "Sort", "Cycle sort order: ID → Name → Image → State → Health → Host (hosts view) → CPU% → Mem → Net → Block (stats mode)"},
+			{"toggle-all", []string{"a"}, "All/Running", "Toggle All / Running-only view"},
+			{"filter", []string{"/"}, "Filter", "Edit the filter: fuzzy words and field:value terms (`Enter`/`Esc` to finish)"},
+			{"saved-filters", []string{"F"}, "Saved", "Pick one of the saved filters"},
+			{"toggle-group", []string{"g"}, "Group", "Toggle grouping by compose project"},
+			{"toggle-fold", []string{"enter"}, "Fold", "Fold / unfold a project header; on a container, same as shell"},
+			{"fold", []string{"left", "h"}, "Fold", "Fold the current project"},
+			{"unfold", []string{"right"}, "", "Unfold the project under the cursor"},
+			{"toggle-stats", []string{"t"}, "Stats", "Toggle stats mode (CPU%, Mem, Net and block I/O rates)"},
+			{"cycle-theme", []string{"T"}, "Theme", "Switch to the next colour theme"},
+			{"stop", []string{"S"}, "Stop", "Stop the highlighted container, project or marked containers"},
+			{"start", []string{"u"}, "Start", "Start (up) the highlighted container, project or marked containers"},
+			{"restart", []string{"R"}, "Restart", "Restart the highlighted container, project or marked containers"},
+			{"pause", []string{"p"}, "Pause", "Pause / unpause the highlighted container, project or marked containers"},
+			{"kill", []string{"K"}, "Kill", "Pick a signal to send to the highlighted container, project or marked containers"},
+			{"remove", []string{"x"}, "Remove", "Remove the highlighted or marked containers — asks for confirmation first"},
+			{"logs", []string{"l"}, "Logs", "Open the log viewer; on a project header, the merged logs of the project"},
+			{"details", []string{"d"}, "Details", "Open the inspect detail pane"},
+			{"health", []string{"H"}, "Health", "Open the health log: the healthcheck and its last probe results"},
+			{"graphs", []string{"G"}, "Graphs", "Open the stats graphs for the highlighted container"},
+			{"processes", []string{"P"}, "Top", "Open the process list of the highlighted container"},
+			{"shell", []string{"i"}, "Shell", "Open a shell in a pane beside the list, with the image's saved exec options or the best shell found; reattaches to the container's running shell if it has one"},
+			{"exec", []string{"I"}, "Exec…", "Open the exec dialog: command, user, working directory and environment for a new shell"},
+			{"open", []string{"o"}, "Open", "Open the container's first public port in the browser"},
+			{"images", []string{"2"}, "Images", "Switch to the images screen"},
+			{"volumes", []string{"3"}, "Volumes", "Switch to the volumes screen"},
+			{"networks", []string{"4"}, "Networks", "Switch to the networks screen"},
+			{"contexts", []string{"c"}, "Context", "Switch to another Docker context; the picker shows where each one points and why it last failed"},
+			{"hosts", []string{"M"}, "Hosts", "Toggle the hosts view: the containers of every host in the config file in one list"},
+			{"quit", []string{"q", "ctrl+c"}, "Quit", "Quit"},
+		}},
+		{"logs", "Log viewer", []binding{
+			{"back", []string{"esc", "q"}, "Back", "Stop following and return to the container list"},
+			{"filter", []string{"/"}, "Filter", "Edit the filter (`Enter`/`Esc` to finish)"},
+			{"streams", []string{"s"}, "stdout/stderr", "Cycle streams: both → stdout → stderr"},
+			{"up", []string{"up", "k"}, "Scroll", "Scroll up (releases the tail lock)"},
+			{"down", []string{"down", "j"}, "Scroll", "Scroll down (re-locks at the bottom)"},
+			{"page-up", []string{"pgup", "ctrl+u"}, "Page", "Scroll a page up"},
+			{"page-down", []string{"pgdown", "ctrl+d"}, "Page", "Scroll a page down"},
+			{"top", []string{"g", "home"}, "Top/Follow", "Jump to the oldest buffered line"},
+			{"follow", []string{"G", "end"}, "Top/Follow", "Jump to the newest line and follow"},
+		}},
+		{"inspect", "Inspect pane", []binding{
+			{"back", []string{"esc", "q"}, "Back", "Return to the container list"},
+			{"up", []string{"up", "k"}, "Scroll", "Scroll up"},
+			{"down", []string{"down", "j"}, "Scroll", "Scroll down"},
+			{"page-up", []string{"pgup", "ctrl+u"}, "Page", "Scroll a page up"},
+			{"page-down", []string{"pgdown", "ctrl+d"}, "Page", "Scroll a page down"},
+			{"top", []string{"g", "home"}, "Top/Bottom", "Jump to the top"},
+			{"bottom", []string{"G", "end"}, "Top/Bottom", "Jump to the bottom"},
+			{"logs", []string{"l"}, "Logs", "Open logs for this container"},
+		}},
+		{"health", "Health log", []binding{
+			{"back", []string{"esc", "q"}, "Back", "Return to the container list"},
+			{"up", []string{"up", "k"}, "Scroll", "Scroll up"},
+			{"down", []string{"down", "j"}, "Scroll", "Scroll down"},
+			{"page-up", []string{"pgup", "ctrl+u"}, "Page", "Scroll a page up"},
+			{"page-down", []string{"pgdown", "ctrl+d"}, "Page", "Scroll a page down"},
+			{"logs", []string{"l"}, "Logs", "Open logs for this container"},
+		}},
+		{"stats", "Stats graphs", []binding{
+			{"back", []string{"esc", "q"}, "Back", "Return to the container list"},
+			{"prev", []string{"up", "k"}, "Prev/Next", "Show the previous container"},
+			{"next", []string{"down", "j"}, "Prev/Next", "Show the next container"},
+			{"logs", []string{"l"}, "Logs", "Open logs for this container"},
+		}},
+		{"top", "Process list", []binding{
+			{"back", []string{"esc", "q"}, "Back", "Return to the container list"},
+			{"up", []string{"up", "k"}, "Nav", "Move cursor up"},
+			{"down", []string{"down", "j"}, "Nav", "Move cursor down"},
+			{"page-up", []string{"pgup", "ctrl+u"}, "", "Move a page up"},
+			{"page-down", []string{"pgdown", "ctrl+d"}, "", "Move a page down"},
+			{"sort", []string{"s"}, "Sort", "Cycle sort order: CPU% → Mem → PID → Command"},
+			{"signal", []string{"K"}, "Signal", "Send a signal to the highlighted process"},
+			{"refresh", []string{"r"}, "Refresh", "Refresh"},
+		}},
+		{"shell", "Shell pane", []binding{
+			{"detach", []string{"ctrl+]"}, "Detach", "Return to the container list, leaving the shell running; every other key goes to the shell"},
+		}},
+		{"images", "Images screen", []binding{
+			{"back", []string{"esc", "q", "1"}, "Back", "Return to the container list; while a pull runs, cancel it instead"},
+			{"volumes", []string{"3"}, "Volumes", "Switch to the volumes screen"},
+			{"networks", []string{"4"}, "Networks", "Switch to the networks screen"},
+			{"up", []string{"up", "k"}, "Nav", "Move cursor up"},
+			{"down", []string{"down", "j"}, "Nav", "Move cursor down"},
+			{"page-up", []string{"pgup", "ctrl+u"}, "", "Move a page up"},
+			{"page-down", []string{"pgdown", "ctrl+d"}, "", "Move a page down"},
+			{"sort", []string{"s"}, "Sort", "Cycle sort order: Repository → Size → Created → Containers"},
+			{"pull", []string{"P"}, "Pull", "Pull an image (prompt pre-filled with the highlighted one)"},
+			{"tag", []string{"t"}, "Tag", "Tag the highlighted image"},
+			{"remove", []string{"x"}, "Remove", "Remove the highlighted image — asks for confirmation first"},
+			{"prune", []string{"D"}, "Prune dangling", "Prune dangling images — shows how much space would be freed"},
+			{"refresh", []string{"r"}, "Refresh", "Refresh"},
+		}},
+		{"volumes", "Volumes screen", []binding{
+			{"back", []string{"esc", "q", "1"}, "Back", "Return to the container list"},
+			{"images", []string{"2"}, "Images", "Switch to the images screen"},
+			{"networks", []string{"4"}, "Networks", "Switch to the networks screen"},
+			{"up", []string{"up", "k"}, "Nav", "Move cursor up"},
+			{"down", []string{"down", "j"}, "Nav", "Move cursor down"},
+			{"page-up", []string{"pgup", "ctrl+u"}, "", "Move a page up"},
+			{"page-down", []string{"pgdown", "ctrl+d"}, "", "Move a page down"},
+			{"select-users", []string{"enter"}, "Select users", "Jump to the container list with the volume's users marked"},
+			{"remove", []string{"x"}, "Remove", "Remove the highlighted volume — asks for confirmation first"},
+			{"prune", []string{"D"}, "Prune unused", "Prune every unused volume — shows count and size first"},
+			{"refresh", []string{"r"}, "Refresh", "Refresh"},
+		}},
+		{"networks", "Networks screen", []binding{
+			{"back", []string{"esc", "q", "1"}, "Back", "Return to the container list"},
+			{"images", []string{"2"}, "Images", "Switch to the images screen"},
+			{"volumes", []string{"3"}, "Volumes", "Switch to the volumes screen"},
+			{"up", []string{"up", "k"}, "Nav", "Move cursor up"},
+			{"down", []string{"down", "j"}, "Nav", "Move cursor down"},
+			{"page-up", []string{"pgup", "ctrl+u"}, "", "Move a page up"},
+			{"page-down", []string{"pgdown", "ctrl+d"}, "", "Move a page down"},
+			{"create", []string{"n"}, "Create", "Create a network with the default driver"},
+			{"remove", []string{"x"}, "Remove", "Remove the highlighted network — asks for confirmation first"},
+			{"connect", []string{"c"}, "Connect target", "Connect the target container to the highlighted network"},
+			{"disconnect", []string{"d"}, "Disconnect target", "Disconnect the target container from the highlighted network"},
+			{"refresh", []string{"r"}, "Refresh", "Refresh"},
+		}},
+		{"confirm", "Confirmation popup", []binding{
+			{"yes", []string{"y", "Y"}, "Yes", "Go ahead"},
+			{"no", []string{"n", "N", "esc"}, "No", "Cancel"},
+		}},
+	}
+}
+
+// keyMap is the active set of bindings, looked up by section and key.
+type keyMap struct {
+	sections []keySection
+	index    map[string]map[string]string // section -> key -> action
+}
+
+// newKeyMap applies the overrides from the config file to the default
+// bindings. overrides maps section -> action -> keys; an empty key list
+// unbinds the action. All problems are reported together.
+func newKeyMap(overrides map[string]map[string][]string) (keyMap, error) {
+	sections := defaultKeySections()
+	var errs []error
+
+	known := make(map[string]int, len(sections))
+	var names []string
+	for i, s := range sections {
+		known[s.name] = i
+		names = append(names, s.name)
+	}
+	for _, name := range sortedKeys(overrides) {
+		i, ok := known[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("keys: unknown section %q (want one of %s)", name, strings.Join(names, ", ")))
+			continue
+		}
+		s := &sections[i]
+		for _, action := range sortedKeys(overrides[name]) {
+			b := s.binding(action)
+			if b == nil {
+				errs = append(errs, fmt.Errorf("keys.%s: unknown action %q", name, action))
+				continue
+			}
+			b.keys = nil
+			for _, k := range overrides[name][action] {
+				if k = strings.TrimSpace(k); k == "" {
+					errs = append(errs, fmt.Errorf("keys.%s.%s: empty key name", name, action))
+					continue
+				}
+				b.keys = append(b.keys, k)
+			}
+		}
+	}
+
+	km := keyMap{sections: sections, index: make(map[string]map[string]string, len(sections))}
+	for _, s := range sections {
+		idx := make(map[string]string)
+		for _, b := range s.bindings {
+			for _, k := range b.keys {
+				if other, dup := idx[keyEventName(k)]; dup && other != b.action {
+					errs = append(errs, fmt.Errorf("keys.%s: %q is bound to both %q and %q", s.name, k, other, b.action))
+					continue
+				}
+				idx[keyEventName(k)] = b.action
+			}
+		}
+		km.index[s.name] = idx
+	}
+	return km, errors.Join(errs...)
+}
+
+// binding returns the named binding of s, or nil.
+func (s *keySection) binding(action string) *binding {
+	for i := range s.bindings {
+		if s.bindings[i].action == action {
+			return &s.bindings[i]
+		}
+	}
+	return nil
+}
+
+// action returns the action bound to key in section, or "" if none is.
+func (km keyMap) action(section, key string) string {
+	return km.index[section][key]
+}
+
+// keysFor returns the display form of the keys bound to an action, e.g.
+// "y/Y", for prompts that mention them.
+func (km keyMap) keysFor(section, action string) string {
+	for _, s := range km.sections {
+		if s.name != section {
+			continue
+		}
+		if b := s.binding(action); b != nil {
+			return displayKeys(b.keys)
+		}
+	}
+	return ""
+}
+
+// footer builds the help line for a section from its active bindings.
+// Consecutive bindings with the same label share one entry, so up and down
+// read "↑/k ↓/j: Nav".
+func (km keyMap) footer(section string) string {
+	var parts []string
+	for _, s := range km.sections {
+		if s.name != section {
+			continue
+		}
+		label, keys := "", []string(nil)
+		flush := func() {
+			if label != "" && len(keys) > 0 {
+				parts = append(parts, strings.Join(keys, " ")+": "+label)
+			}
+		}
+		for _, b := range s.bindings {
+			if b.footer != label {
+				flush()
+				label, keys = b.footer, nil
+			}
+			if b.footer != "" && len(b.keys) > 0 {
+				keys = append(keys, displayKeys(b.keys))
+			}
+		}
+		flush()
+	}
+	return strings.Join(parts, " • ")
+}
+
+// markdown renders the keymap as the tables shown in the README.
+func (km keyMap) markdown() string {
+	var sb strings.Builder
+	for i, s := range km.sections {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "### %s (`%s`)\n\n", s.title, s.name)
+		sb.WriteString("| Key | Action | Description |\n|-----|--------|-------------|\n")
+		for _, b := range s.bindings {
+			keys := "_unbound_"
+			if len(b.keys) > 0 {
+				var quoted []string
+				for _, k := range b.keys {
+					quoted = append(quoted, "`"+displayKey(k)+"`")
+				}
+				keys = strings.Join(quoted, " / ")
+			}
+			fmt.Fprintf(&sb, "| %s | `%s` | %s |\n", keys, b.action, b.help)
+		}
+	}
+	return sb.String()
+}
+
+// keyEventName maps a configured key name to what tea.KeyMsg.String()
+// reports for it.
+func keyEventName(k string) string {
+	if k == "space" {
+		return " "
+	}
+	return k
+}
+
+var keySymbols = map[string]string{
+	"up": "↑", "down": "↓", "left": "←", "right": "→",
+	"esc": "Esc", "enter": "Enter", "space": "Space", "tab": "Tab",
+	"backspace": "Backspace", "delete": "Del",
+	"pgup": "PgUp", "pgdown": "PgDn", "home": "Home", "end": "End",
+}
+
+// displayKey is how a key is written in help text: arrows as symbols and
+// modifiers capitalised, e.g. "Ctrl+D".
+func displayKey(k string) string {
+	if s, ok := keySymbols[k]; ok {
+		return s
+	}
+	if mod, rest, ok := strings.Cut(k, "+"); ok && len(mod) > 1 {
+		return strings.ToUpper(mod[:1]) + mod[1:] + "+" + strings.ToUpper(rest)
+	}
+	return k
+}
+
+func displayKeys(keys []string) string {
+	out := make([]string, len(keys))
+	for i, k := range keys {
+		out[i] = displayKey(k)
+	}
+	return strings.Join(out, "/")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
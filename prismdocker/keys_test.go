@@ -0,0 +1,129 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewKeyMap(t *testing.T) {
+	type lookup struct {
+		section, key, want string
+	}
+	tests := []struct {
+		name      string
+		overrides map[string]map[string][]string
+		lookups   []lookup
+		wantErrs  []string
+	}{
+		{
+			name: "defaults",
+			lookups: []lookup{
+				{"containers", "q", "quit"},
+				{"containers", "k", "up"},
+				{"containers", " ", "mark"},
+				{"containers", "nope", ""},
+			},
+		},
+		{
+			name: "rebinding replaces the default keys",
+			overrides: map[string]map[string][]string{
+				"containers": {"quit": {"f10", " ctrl+q "}},
+			},
+			lookups: []lookup{
+				{"containers", "f10", "quit"},
+				{"containers", "ctrl+q", "quit"},
+				{"containers", "q", ""},
+			},
+		},
+		{
+			name: "an empty list unbinds",
+			overrides: map[string]map[string][]string{
+				"containers": {"mark": {}},
+			},
+			lookups: []lookup{{"containers", " ", ""}},
+		},
+		{
+			name: "sections are independent",
+			overrides: map[string]map[string][]string{
+				"logs": {"up": {"w"}},
+			},
+			lookups: []lookup{
+				{"logs", "w", "up"},
+				{"containers", "k", "up"},
+			},
+		},
+		{
+			name: "conflict",
+			overrides: map[string]map[string][]string{
+				"containers": {"quit": {"k"}},
+			},
+			wantErrs: []string{`keys.containers: "k" is bound to both`},
+		},
+		{
+			name: "conflict on a named key",
+			overrides: map[string]map[string][]string{
+				"containers": {"mark": {"space"}, "up": {"space"}},
+			},
+			wantErrs: []string{`keys.containers: "space" is bound to both`},
+		},
+		{
+			name: "unknown section, action and empty key",
+			overrides: map[string]map[string][]string{
+				"nowhere":    {"quit": {"q"}},
+				"containers": {"fly": {"f"}, "quit": {"  "}},
+			},
+			wantErrs: []string{
+				`keys.containers: unknown action "fly"`,
+				"keys.containers.quit: empty key name",
+				`keys: unknown section "nowhere"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			km, err := newKeyMap(tt.overrides)
+			checkErrs(t, err, tt.wantErrs)
+			for _, l := range tt.lookups {
+				if got := km.action(l.section, l.key); got != l.want {
+					t.Errorf("action(%q, %q) = %q, want %q", l.section, l.key, got, l.want)
+				}
+			}
+		})
+	}
+}
+
+func TestDisplayKeys(t *testing.T) {
+	tests := []struct {
+		keys []string
+		want string
+	}{
+		{[]string{"up", "k"}, "↑/k"},
+		{[]string{"ctrl+d", "pgdown"}, "Ctrl+D/PgDn"},
+		{[]string{"space"}, "Space"},
+		{[]string{"+"}, "+"},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := displayKeys(tt.keys); got != tt.want {
+			t.Errorf("displayKeys(%q) = %q, want %q", tt.keys, got, tt.want)
+		}
+	}
+}
+
+// checkErrs fails the test unless err holds exactly one problem per entry
+// of want, each containing that entry.
+func checkErrs(t *testing.T, err error, want []string) {
+	t.Helper()
+	var got []string
+	if err != nil {
+		got = strings.Split(err.Error(), "\n")
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d errors %q, want %d like %q", len(got), got, len(want), want)
+	}
+	for i := range want {
+		if !strings.Contains(got[i], want[i]) {
+			t.Errorf("error %d = %q, want it to contain %q", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+// listState is the cursor and scroll position of a full-screen table such
+// as the images or volumes screen.
+type listState struct {
+	cursor int
+	offset int
+}
+
+// move shifts the cursor by delta within n rows and scrolls so it stays
+// inside a window of height rows.
+func (l *listState) move(delta, n, height int) {
+	l.cursor += delta
+	if l.cursor >= n {
+		l.cursor = n - 1
+	}
+	if l.cursor < 0 {
+		l.cursor = 0
+	}
+	if l.cursor < l.offset {
+		l.offset = l.cursor
+	}
+	if height < 1 {
+		height = 1
+	}
+	if l.cursor >= l.offset+height {
+		l.offset = l.cursor - height + 1
+	}
+	if l.offset > 0 && l.offset > n-height {
+		l.offset = n - height
+		if l.offset < 0 {
+			l.offset = 0
+		}
+	}
+}
+
+// window returns the [start, end) range of rows to draw.
+func (l listState) window(n, height int) (int, int) {
+	start := l.offset
+	if start > n {
+		start = n
+	}
+	end := start + height
+	if end > n {
+		end = n
+	}
+	return start, end
+}
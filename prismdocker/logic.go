@@ -4,10 +4,14 @@ import (
 	"sort"
 )
 
-func sortAndFilter(containers []Container, order SortOrder, showAll bool, stats map[string]Stats) []Container {
+func sortAndFilter(containers []Container, order SortOrder, showAll bool, stats map[string]Stats, filter containerFilter) []Container {
 	var filtered []Container
 	for _, c := range containers {
-		if !showAll && c.State != "running" {
+		// A filter on state overrides the Running-only view
+		if !showAll && c.State != "running" && !filter.setsState() {
+			continue
+		}
+		if !filter.matches(c) {
 			continue
 		}
 		filtered = append(filtered, c)
@@ -18,9 +22,12 @@ func sortAndFilter(containers []Container, order SortOrder, showAll bool, stats
 		case SortByID:
 			return filtered[i].ID < filtered[j].ID
 		case SortByName:
-			return filtered[i].Names < filtered[j].Names
+			return byNameAndHost(filtered[i], filtered[j])
 		case SortByImage:
-			return filtered[i].Image < filtered[j].Image
+			if filtered[i].Image != filtered[j].Image {
+				return filtered[i].Image < filtered[j].Image
+			}
+			return filtered[i].Host < filtered[j].Host
 		case SortByState:
 			// Sort by state (running first), then by name
 			if filtered[i].State != filtered[j].State {
@@ -32,15 +39,39 @@ func sortAndFilter(containers []Container, order SortOrder, showAll bool, stats
 				}
 				return filtered[i].State < filtered[j].State
 			}
+			return byNameAndHost(filtered[i], filtered[j])
+		case SortByHealth:
+			// Unhealthy first, then by name
+			ri, rj := healthRank(filtered[i].Health), healthRank(filtered[j].Health)
+			if ri != rj {
+				return ri < rj
+			}
+			return byNameAndHost(filtered[i], filtered[j])
+		case SortByHost:
+			// By host, and within one like the default State order
+			if filtered[i].Host != filtered[j].Host {
+				return filtered[i].Host < filtered[j].Host
+			}
+			if (filtered[i].State == "running") != (filtered[j].State == "running") {
+				return filtered[i].State == "running"
+			}
 			return filtered[i].Names < filtered[j].Names
 		case SortByCPU:
-			si := stats[filtered[i].ID]
-			sj := stats[filtered[j].ID]
+			si := stats[filtered[i].key()]
+			sj := stats[filtered[j].key()]
 			return si.CPUPercent > sj.CPUPercent // descending
 		case SortByMem:
-			si := stats[filtered[i].ID]
-			sj := stats[filtered[j].ID]
+			si := stats[filtered[i].key()]
+			sj := stats[filtered[j].key()]
 			return si.MemUsage > sj.MemUsage // descending
+		case SortByNet:
+			si := stats[filtered[i].key()]
+			sj := stats[filtered[j].key()]
+			return si.NetRxRate+si.NetTxRate > sj.NetRxRate+sj.NetTxRate // descending
+		case SortByBlock:
+			si := stats[filtered[i].key()]
+			sj := stats[filtered[j].key()]
+			return si.BlkReadRate+si.BlkWriteRate > sj.BlkReadRate+sj.BlkWriteRate // descending
 		default:
 			return filtered[i].ID < filtered[j].ID
 		}
@@ -48,3 +79,12 @@ func sortAndFilter(containers []Container, order SortOrder, showAll bool, stats
 
 	return filtered
 }
+
+// byNameAndHost orders by name, and a name shared by several hosts (the
+// same stack on each) by host, so those containers end up side by side.
+func byNameAndHost(a, b Container) bool {
+	if a.Names != b.Names {
+		return a.Names < b.Names
+	}
+	return a.Host < b.Host
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortByHealth(t *testing.T) {
+	containers := []Container{
+		{ID: "1", Names: "web", State: "running", Health: "healthy"},
+		{ID: "2", Names: "db", State: "running", Health: "unhealthy"},
+		{ID: "3", Names: "cache", State: "running"},
+		{ID: "4", Names: "api", State: "running", Health: "starting"},
+		{ID: "5", Names: "auth", State: "running", Health: "unhealthy"},
+		{ID: "6", Names: "queue", State: "running", Health: "healthy"},
+	}
+	got := sortAndFilter(containers, SortByHealth, true, nil, containerFilter{})
+	var names []string
+	for _, c := range got {
+		names = append(names, c.Names)
+	}
+	want := []string{"auth", "db", "api", "queue", "web", "cache"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("sorted by health: %q, want %q", names, want)
+	}
+}
+
+func TestSortByNameAndHost(t *testing.T) {
+	containers := []Container{
+		{ID: "1", Names: "web", Host: "prod", State: "running", Image: "nginx"},
+		{ID: "2", Names: "db", Host: "staging", State: "running", Image: "postgres"},
+		{ID: "3", Names: "web", Host: "edge", State: "running", Image: "nginx"},
+		{ID: "4", Names: "db", Host: "prod", State: "exited", Image: "postgres"},
+		{ID: "5", Names: "web", Host: "staging", State: "exited", Image: "nginx"},
+	}
+	tests := []struct {
+		order SortOrder
+		want  []string
+	}{
+		{SortByName, []string{"db@prod", "db@staging", "web@edge", "web@prod", "web@staging"}},
+		{SortByState, []string{"db@staging", "web@edge", "web@prod", "db@prod", "web@staging"}},
+		{SortByImage, []string{"web@edge", "web@prod", "web@staging", "db@prod", "db@staging"}},
+		{SortByHost, []string{"web@edge", "web@prod", "db@prod", "db@staging", "web@staging"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, c := range sortAndFilter(containers, tt.order, true, nil, containerFilter{}) {
+			got = append(got, c.Names+"@"+c.Host)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sorted by %s: %q, want %q", tt.order, got, tt.want)
+		}
+	}
+}
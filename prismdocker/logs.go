@@ -2,39 +2,378 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/moby/moby/client"
 )
 
-type logLinesMsg []string
+// maxLogLines caps the log buffer so a chatty container can't grow it forever.
+const maxLogLines = 5000
 
-func fetchLogs(cli *client.Client, containerID string) tea.Cmd {
+// logBatchSize is how many queued lines are folded into a single message,
+// so a burst of output causes one re-render instead of hundreds.
+const logBatchSize = 256
+
+// LogSource identifies which output stream a log line was written to.
+type LogSource int
+
+const (
+	sourceStdout LogSource = iota
+	sourceStderr
+)
+
+// logLine is a single line of container output tagged with its stream.
+// Prefix names the service it came from when several containers' logs are
+// merged, and is empty otherwise.
+type logLine struct {
+	Source LogSource
+	Text   string
+	Prefix string
+}
+
+// logTarget is one container to follow; prefix is stamped on its lines.
+type logTarget struct {
+	cli    *client.Client
+	id     string
+	prefix string
+}
+
+// LogSourceFilter selects which streams the log viewer shows.
+type LogSourceFilter int
+
+const (
+	showBothStreams LogSourceFilter = iota
+	showStdoutOnly
+	showStderrOnly
+)
+
+func (f LogSourceFilter) String() string {
+	switch f {
+	case showStdoutOnly:
+		return "stdout"
+	case showStderrOnly:
+		return "stderr"
+	default:
+		return "both"
+	}
+}
+
+func (f LogSourceFilter) allows(src LogSource) bool {
+	switch f {
+	case showStdoutOnly:
+		return src == sourceStdout
+	case showStderrOnly:
+		return src == sourceStderr
+	default:
+		return true
+	}
+}
+
+// logLinesMsg carries newly received lines from a follow stream.
+type logLinesMsg struct {
+	session int
+	lines   []logLine
+}
+
+// logStreamEndMsg is sent once a follow stream has finished and every line
+// it read has been delivered, either because the container stopped, the
+// stream was cancelled or the daemon errored.
+type logStreamEndMsg struct {
+	session int
+	err     error
+}
+
+// logStream is a long-lived `docker logs --follow` subscription. Lines are
+// pushed onto a channel by the reader and picked up by waitForLogLines.
+type logStream struct {
+	session int
+	lines   chan logLine
+	err     error // why the stream ended; set before lines is closed
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+func newLogStream(session int) *logStream {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &logStream{
+		session: session,
+		lines:   make(chan logLine, logBatchSize),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Stop cancels the underlying request. It is safe to call more than once.
+func (s *logStream) Stop() {
+	if s != nil {
+		s.cancel()
+	}
+}
+
+// followLogs runs the stream until it ends. How it ended is reported by
+// waitForLogLines once the lines still queued have been picked up. Each
+// target starts with its last 500 lines and then keeps following new
+// output; with several targets their lines are interleaved as they arrive
+// and the stream ends once every one of them has.
+func followLogs(s *logStream, targets ...logTarget) tea.Cmd {
 	return func() tea.Msg {
-		rc, err := cli.ContainerLogs(context.Background(), containerID, client.ContainerLogsOptions{
-			ShowStdout: true,
-			ShowStderr: true,
-			Tail:       "500",
-		})
-		if err != nil {
-			return logLinesMsg([]string{"Error fetching logs: " + err.Error()})
-		}
-		defer rc.Close()
-
-		var lines []string
-		scanner := bufio.NewScanner(rc)
+		errs := make([]error, len(targets))
+		var wg sync.WaitGroup
+		for i, t := range targets {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = followContainerLogs(s, t)
+			}()
+		}
+		wg.Wait()
+
+		if s.ctx.Err() == nil {
+			s.err = errors.Join(errs...)
+		}
+		close(s.lines)
+		return nil
+	}
+}
+
+// followContainerLogs copies one container's log lines onto the stream.
+func followContainerLogs(s *logStream, t logTarget) error {
+	cli := t.cli
+	// TTY containers write a raw byte stream; everything else is
+	// multiplexed, so we need to know which one we're about to read.
+	info, err := cli.ContainerInspect(s.ctx, t.id, client.ContainerInspectOptions{})
+	if err != nil {
+		return err
+	}
+	tty := info.Container.Config != nil && info.Container.Config.Tty
+
+	rc, err := cli.ContainerLogs(s.ctx, t.id, client.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+		Tail:       "500",
+	})
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	err = readLogStream(rc, tty, func(l logLine) bool {
+		l.Prefix = t.prefix
+		select {
+		case s.lines <- l:
+			return true
+		case <-s.ctx.Done():
+			return false
+		}
+	})
+	if errors.Is(err, context.Canceled) {
+		err = nil
+	}
+	if err != nil && t.prefix != "" {
+		err = fmt.Errorf("%s: %w", t.prefix, err)
+	}
+	return err
+}
+
+// Stream types used in the Docker multiplex frame header.
+const (
+	frameStdin  = 0
+	frameStdout = 1
+	frameStderr = 2
+	frameSystem = 3
+)
+
+// maxFrameSize bounds a single multiplexed frame. The daemon splits output
+// into frames far smaller than this, so a bigger size means the stream is
+// corrupt rather than that a buffer that large should be allocated.
+const maxFrameSize = 1024 * 1024
+
+// readLogStream splits a log stream into lines and hands each one to emit.
+// For TTY containers the stream is raw output and every line counts as
+// stdout. Otherwise it is a sequence of frames, each prefixed by an 8-byte
+// header: one byte of stream type, three padding bytes and a big-endian
+// uint32 payload size. A frame may hold several lines or only part of one,
+// so partial lines are buffered per stream until their newline arrives.
+// Reading stops early if emit returns false.
+func readLogStream(r io.Reader, tty bool, emit func(logLine) bool) error {
+	if tty {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
 		for scanner.Scan() {
-			line := scanner.Text()
-			// Docker multiplexes stdout/stderr with an 8-byte header; strip it
-			if len(line) > 8 {
-				line = line[8:]
+			text := strings.TrimSuffix(scanner.Text(), "\r")
+			if !emit(logLine{Source: sourceStdout, Text: text}) {
+				return nil
+			}
+		}
+		return scanner.Err()
+	}
+
+	var (
+		header  [8]byte
+		payload []byte
+		pending = map[LogSource]*bytes.Buffer{
+			sourceStdout: {},
+			sourceStderr: {},
+		}
+	)
+	// flush emits the partial lines left over and reports whether emit
+	// still wants more.
+	flush := func() bool {
+		for _, src := range []LogSource{sourceStdout, sourceStderr} {
+			if buf := pending[src]; buf.Len() > 0 {
+				text := buf.String()
+				buf.Reset()
+				if !emit(logLine{Source: src, Text: text}) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	for {
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			flush()
+			if errors.Is(err, io.EOF) {
+				return nil
 			}
-			lines = append(lines, line)
+			return err
+		}
+		size := int(binary.BigEndian.Uint32(header[4:]))
+		if size > maxFrameSize {
+			flush()
+			return fmt.Errorf("malformed log stream: %d byte frame", size)
+		}
+		if cap(payload) < size {
+			payload = make([]byte, size)
 		}
-		if len(lines) == 0 {
-			lines = []string{"(no logs)"}
+		payload = payload[:size]
+		if _, err := io.ReadFull(r, payload); err != nil {
+			flush()
+			return err
+		}
+
+		var src LogSource
+		switch header[0] {
+		case frameStdin, frameStdout:
+			src = sourceStdout
+		case frameStderr:
+			src = sourceStderr
+		case frameSystem:
+			// The daemon reports its own errors on this stream
+			if !flush() {
+				return nil
+			}
+			emit(logLine{Source: sourceStderr, Text: "daemon error: " + strings.TrimSpace(string(payload))})
+			return errors.New(strings.TrimSpace(string(payload)))
+		default:
+			return errors.New("malformed log stream: unknown stream type")
+		}
+
+		buf := pending[src]
+		buf.Write(payload)
+		for {
+			data := buf.Bytes()
+			i := bytes.IndexByte(data, '\n')
+			if i < 0 {
+				break
+			}
+			text := strings.TrimSuffix(string(data[:i]), "\r")
+			buf.Next(i + 1)
+			if !emit(logLine{Source: src, Text: text}) {
+				return nil
+			}
 		}
-		return logLinesMsg(lines)
 	}
 }
+
+// waitForLogLines blocks until at least one line is available and then
+// drains whatever else is already queued. Once the stream's channel is
+// closed and empty it reports how the stream ended, which ends the wait
+// loop.
+func waitForLogLines(s *logStream) tea.Cmd {
+	return func() tea.Msg {
+		line, ok := <-s.lines
+		if !ok {
+			return logStreamEndMsg{s.session, s.err}
+		}
+		lines := []logLine{line}
+		for len(lines) < logBatchSize {
+			select {
+			case line, ok := <-s.lines:
+				if !ok {
+					return logLinesMsg{s.session, lines}
+				}
+				lines = append(lines, line)
+			default:
+				return logLinesMsg{s.session, lines}
+			}
+		}
+		return logLinesMsg{s.session, lines}
+	}
+}
+
+// visibleLogLines returns the log buffer with the stream and text filters
+// applied.
+func (m model) visibleLogLines() []logLine {
+	if m.logFilter == "" && m.logSources == showBothStreams {
+		return m.logLines
+	}
+	needle := strings.ToLower(m.logFilter)
+	var filtered []logLine
+	for _, l := range m.logLines {
+		if !m.logSources.allows(l.Source) {
+			continue
+		}
+		if needle != "" && !strings.Contains(strings.ToLower(l.Prefix+" "+l.Text), needle) {
+			continue
+		}
+		filtered = append(filtered, l)
+	}
+	return filtered
+}
+
+// logBodyHeight is the number of log lines that fit on screen.
+func (m model) logBodyHeight() int {
+	// title + blank line, footer with its top margin, and one spare line
+	h := m.height - 2 - 2 - 1
+	if m.logFilterMode || m.logFilter != "" {
+		h--
+	}
+	if h < 1 {
+		h = 1
+	}
+	return h
+}
+
+// maxLogOffset is the offset at which the last log line sits at the bottom.
+func (m model) maxLogOffset() int {
+	max := len(m.visibleLogLines()) - m.logBodyHeight()
+	if max < 0 {
+		return 0
+	}
+	return max
+}
+
+// closeLogs stops any running stream and resets the log viewer state.
+func (m *model) closeLogs() {
+	m.logStream.Stop()
+	m.logStream = nil
+	m.logSession++
+	m.logLines = nil
+	m.logFilter = ""
+	m.logFilterMode = false
+	m.logOffset = 0
+	m.logFollow = false
+	m.logStreamErr = nil
+	m.logSources = showBothStreams
+}
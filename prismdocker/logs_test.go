@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// frame builds one multiplexed log frame.
+func frame(stream byte, payload string) []byte {
+	header := make([]byte, 8)
+	header[0] = stream
+	binary.BigEndian.PutUint32(header[4:], uint32(len(payload)))
+	return append(header, payload...)
+}
+
+func frames(fs ...[]byte) []byte {
+	return bytes.Join(fs, nil)
+}
+
+func TestReadLogStream(t *testing.T) {
+	out := func(text string) logLine { return logLine{Source: sourceStdout, Text: text} }
+	errLine := func(text string) logLine { return logLine{Source: sourceStderr, Text: text} }
+
+	tests := []struct {
+		name    string
+		tty     bool
+		input   []byte
+		want    []logLine
+		wantErr string
+	}{
+		{
+			name:  "tty lines",
+			tty:   true,
+			input: []byte("one\r\ntwo\nthree"),
+			want:  []logLine{out("one"), out("two"), out("three")},
+		},
+		{
+			name:  "tty empty",
+			tty:   true,
+			input: nil,
+		},
+		{
+			name:  "one line per frame",
+			input: frames(frame(frameStdout, "one\n"), frame(frameStderr, "two\n")),
+			want:  []logLine{out("one"), errLine("two")},
+		},
+		{
+			name:  "several lines in one frame",
+			input: frame(frameStdout, "one\r\ntwo\nthree\n"),
+			want:  []logLine{out("one"), out("two"), out("three")},
+		},
+		{
+			name: "line split across frames of both streams",
+			input: frames(
+				frame(frameStdout, "hel"),
+				frame(frameStderr, "oops\n"),
+				frame(frameStdout, "lo\n"),
+			),
+			want: []logLine{errLine("oops"), out("hello")},
+		},
+		{
+			name:  "partial lines are flushed at the end",
+			input: frames(frame(frameStdout, "no newline"), frame(frameStderr, "nor here")),
+			want:  []logLine{out("no newline"), errLine("nor here")},
+		},
+		{
+			name:  "stdin counts as stdout",
+			input: frame(frameStdin, "typed\n"),
+			want:  []logLine{out("typed")},
+		},
+		{
+			name:    "daemon error",
+			input:   frames(frame(frameStdout, "last"), frame(frameSystem, "driver failed\n")),
+			want:    []logLine{out("last"), errLine("daemon error: driver failed")},
+			wantErr: "driver failed",
+		},
+		{
+			name:    "unknown stream type",
+			input:   frame(7, "x\n"),
+			wantErr: "unknown stream type",
+		},
+		{
+			name:    "oversized frame",
+			input:   frame(frameStdout, strings.Repeat("x", maxFrameSize+1)),
+			wantErr: "malformed log stream",
+		},
+		{
+			name:    "truncated header",
+			input:   []byte{frameStdout, 0, 0},
+			wantErr: "unexpected EOF",
+		},
+		{
+			name:    "truncated payload",
+			input:   frame(frameStdout, "complete\n")[:10],
+			wantErr: "unexpected EOF",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []logLine
+			err := readLogStream(bytes.NewReader(tt.input), tt.tty, func(l logLine) bool {
+				got = append(got, l)
+				return true
+			})
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lines = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadLogStreamStopsWhenEmitDeclines(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		tty   bool
+		input []byte
+	}{
+		{"tty", true, []byte("one\ntwo\nthree\n")},
+		{"multiplexed", false, frame(frameStdout, "one\ntwo\nthree\n")},
+		{"partial lines", false, frames(frame(frameStdout, "one"), frame(frameStderr, "two"), frame(frameSystem, "boom"))},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := readLogStream(bytes.NewReader(tt.input), tt.tty, func(logLine) bool {
+				calls++
+				return false
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if calls != 1 {
+				t.Errorf("emit called %d times, want 1", calls)
+			}
+		})
+	}
+}
@@ -1,14 +1,131 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
-	p := tea.NewProgram(initialModel(), tea.WithAltScreen())
+	configPath := flag.String("config", "", "path to the config file (default: prismdocker/config.json in the user config dir)")
+	printKeymap := flag.Bool("print-keymap", false, "print the active keybindings as Markdown tables and exit")
+	showHosts := flag.Bool("hosts", false, "start in the hosts view: the containers of every host in the config file")
+	flag.Parse()
+
+	if *configPath == "" {
+		// Without a home directory there is no default config; run with
+		// the built-in settings.
+		if path, err := defaultConfigPath(); err == nil {
+			*configPath = path
+		}
+	}
+	var cfg Config
+	if *configPath != "" {
+		var err error
+		if cfg, err = loadConfig(*configPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid config: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	keys, err := newKeyMap(cfg.Keys)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid keybindings in %s:\n%v\n", *configPath, err)
+		os.Exit(1)
+	}
+
+	themes, err := loadThemes(cfg.Themes)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid themes in %s:\n%v\n", *configPath, err)
+		os.Exit(1)
+	}
+	themeIndex := 0
+	if cfg.Theme != "" {
+		if themeIndex = findTheme(themes, cfg.Theme); themeIndex < 0 {
+			fmt.Fprintf(os.Stderr, "Invalid config: unknown theme %q\n", cfg.Theme)
+			os.Exit(1)
+		}
+	}
+	applyTheme(themes[themeIndex])
+
+	var stopTimeout *int
+	if cfg.StopTimeout != "" {
+		d, err := time.ParseDuration(cfg.StopTimeout)
+		if err != nil || d < 0 {
+			fmt.Fprintf(os.Stderr, "Invalid config: stop_timeout: %q is not a duration like 30s\n", cfg.StopTimeout)
+			os.Exit(1)
+		}
+		stopTimeout = ptr(int(d.Round(time.Second) / time.Second))
+	}
+
+	rules, err := loadAlertRules(cfg.Alerts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid alerts in %s:\n%v\n", *configPath, err)
+		os.Exit(1)
+	}
+	notifyRules, err := loadNotifyRules(cfg.Notifications)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid notifications in %s:\n%v\n", *configPath, err)
+		os.Exit(1)
+	}
+
+	execDefaults, err := loadExecDefaults(cfg.Exec)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid exec defaults in %s:\n%v\n", *configPath, err)
+		os.Exit(1)
+	}
+
+	savedFilters, err := loadSavedFilters(cfg.Filters)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid filters in %s:\n%v\n", *configPath, err)
+		os.Exit(1)
+	}
+
+	hosts, err := loadHosts(cfg.Hosts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid hosts in %s:\n%v\n", *configPath, err)
+		os.Exit(1)
+	}
+	if *showHosts && len(hosts) == 0 {
+		fmt.Fprintf(os.Stderr, "No hosts in %s for -hosts to show\n", *configPath)
+		os.Exit(1)
+	}
+
+	if *printKeymap {
+		fmt.Print(keys.markdown())
+		return
+	}
+
+	// A broken context shouldn't keep Prism from starting; the usable ones
+	// are still there to pick from.
+	contexts, contextName, contextsErr := loadDockerContexts()
+
+	m := initialModel(modelOptions{
+		keys:         keys,
+		themes:       themes,
+		themeIndex:   themeIndex,
+		alertRules:   rules,
+		notifyRules:  notifyRules,
+		execDefaults: execDefaults,
+		savedFilters: savedFilters,
+		configPath:   *configPath,
+		contexts:     contexts,
+		contextName:  contextName,
+		hosts:        hosts,
+		stopTimeout:  stopTimeout,
+	})
+	if contextsErr != nil {
+		m.statusMsg = "Skipped Docker contexts: " + strings.ReplaceAll(contextsErr.Error(), "\n", "; ")
+		m.statusTick = 10
+	}
+	if *showHosts {
+		m.openHosts()
+	}
+	m.output = newTermOutput(os.Stdout)
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithOutput(m.output))
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Alas, there's been an error: %v", err)
 		os.Exit(1)
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"time"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/moby/moby/client"
 )
@@ -12,6 +14,14 @@ type ActiveView int
 const (
 	viewContainers ActiveView = iota
 	viewLogs
+	viewInspect
+	viewImages
+	viewVolumes
+	viewNetworks
+	viewStats
+	viewTop
+	viewHealth
+	viewShell
 )
 
 const (
@@ -19,8 +29,12 @@ const (
 	SortByName
 	SortByImage
 	SortByState
+	SortByHealth
+	SortByHost
 	SortByCPU
 	SortByMem
+	SortByNet
+	SortByBlock
 )
 
 func (s SortOrder) String() string {
@@ -33,21 +47,51 @@ func (s SortOrder) String() string {
 		return "Image"
 	case SortByState:
 		return "State"
+	case SortByHealth:
+		return "Health"
+	case SortByHost:
+		return "Host"
 	case SortByCPU:
 		return "CPU%"
 	case SortByMem:
 		return "Mem"
+	case SortByNet:
+		return "Net"
+	case SortByBlock:
+		return "Block"
 	default:
 		return "Unknown"
 	}
 }
 
+// needsStats reports whether the order sorts on stats, which are only
+// there in stats mode.
+func (s SortOrder) needsStats() bool {
+	return s >= SortByCPU
+}
+
+// nextSortOrder is the order after the current one that applies: the
+// stats orders only in stats mode, Host only in the hosts view.
+func (m model) nextSortOrder() SortOrder {
+	s := m.sortOrder
+	for {
+		s = (s + 1) % (SortByBlock + 1)
+		if (s.needsStats() && !m.showStats) || (s == SortByHost && m.hosts == nil) {
+			continue
+		}
+		return s
+	}
+}
+
 type model struct {
 	dockerClient       *client.Client
+	keys               keyMap
+	themes             []Theme
+	themeIndex         int // index of the active theme in themes
 	allContainers      []Container
 	filteredContainers []Container
+	rows               []tableRow // table layout: project headers and containers
 	cursor             int
-	err                error
 	width              int
 	height             int
 	sortOrder          SortOrder
@@ -56,29 +100,146 @@ type model struct {
 	tableOffset        int
 	showStats          bool
 	stats              map[string]Stats
+	statsHistory       map[string]*statsRing // recent samples, kept while stats are fetched
+	graphKey           string                // container shown on the stats screen
+	statsCollector     *statsCollector       // running while stats mode or the graphs screen is on
+	statsSession       int                   // bumped per collector so stale snapshots are dropped
+	statsPending       bool                  // a snapshot has been asked for and not yet arrived
+	// Row alerts
+	alertRules    []alertRule
+	alertHolds    map[alertKey]*alertHold // rules whose condition currently holds
+	alerts        map[string]alertLevel   // container key -> level of its firing rules
+	restartCounts map[string]int          // fetched only while a rule watches restarts
+	// Notifications
+	notifyRules []notifyRule
+	notifyLast  map[notifyKey]time.Time // last delivery per rule, container and event
+	notifySent  []time.Time             // deliveries in the last notifyWindow, oldest first
+	output      *termOutput             // the program's output, which the bell is rung on
+	// Compose project grouping
+	groupByProject bool
+	collapsed      map[string]bool // folded projects, by name
+	// Docker contexts; switching one tears down everything of the last
+	contexts    []dockerContext
+	contextName string           // the context dockerClient talks to
+	contextErrs map[string]error // last connection error per context, cleared once it answers
+	// Hosts view: the containers of every configured host in one list
+	hostConfigs []hostConfig
+	hosts       []*daemonHost // nil outside the hosts view
+	// Filter bar
+	filterQuery  string
+	filterMode   bool            // the bar is being edited
+	filter       containerFilter // last valid parse of filterQuery
+	filterErr    error
+	savedFilters map[string]string // by name, as in the config file
+	// Stop and restart give a container this many seconds to exit; nil
+	// leaves it to the daemon
+	stopTimeout *int
 	// Action confirm dialog
 	confirmMode   bool
-	confirmAction string // "remove"
+	confirmAction string // "remove", "remove-selected", "remove-image", ...
+	confirmText   string // question shown in the popup
+	confirmTarget string // what the action applies to: container ID, image, volume, ...
+	// One-line text input shown above the footer
+	prompt *textPrompt
+	// Popup list of fixed choices
+	picker *picker
+	// Multi-select and batch actions
+	selected     map[string]bool // keys of the containers marked with space
+	batch        *batchRun
+	batchSeq     int
+	batchSummary bool // failure summary popup is showing
 	// Brief status message shown in footer
 	statusMsg  string
 	statusTick int // countdown to clear statusMsg
 	// Log viewer
 	activeView    ActiveView
-	logLines      []string
+	logLines      []logLine
 	logFilter     string
 	logFilterMode bool
 	logOffset     int
-	logContainer  string // ID of container being viewed
+	logTitle      string // container ID, or the project for merged logs
+	logStream     *logStream
+	logSession    int   // bumped whenever a stream is closed so its stale messages are dropped
+	logFollow     bool  // tail lock: keep the view pinned to the newest line
+	logStreamErr  error // why the stream ended, if it ended badly
+	logSources    LogSourceFilter
+	// Inspect detail pane
+	inspectID     string
+	inspectHost   string
+	inspectName   string
+	inspectLines  []string
+	inspectErr    error
+	inspectOffset int
+	// Health log
+	healthID     string
+	healthHost   string
+	healthName   string
+	healthLines  []string
+	healthErr    error
+	healthOffset int
+	// Process list
+	topID        string
+	topHost      string
+	topName      string
+	processes    []Process
+	processesErr error
+	processList  listState
+	processSort  ProcessSortOrder
+	// Shell pane; sessions stay open when detached
+	shells         map[string]*shellSession // by container key
+	shellsStarting map[string]bool          // containers with a session on its way
+	shellKey       string                   // container whose session the pane shows
+	// Exec options
+	execDialog   *execDialog
+	execDefaults map[string]ExecOptions // by image repository, as in the config file
+	configPath   string                 // where the exec dialog saves them
+	// Images screen
+	images      []Image
+	imagesErr   error
+	imageList   listState
+	imageSort   ImageSortOrder
+	pull        *imagePull
+	pullSession int
+	// Volumes screen
+	volumes    []Volume
+	volumesErr error
+	volumeList listState
+	// Networks screen
+	networks    []Network
+	networksErr error
+	networkList listState
+	// Docker event stream; while it is live the list is updated per event
+	// instead of being polled every tick
+	events        *eventStream
+	eventsSession int
+	eventsLive    bool
+	eventsRetryIn int // ticks until the next reconnect attempt
+	eventsBackoff int // current reconnect delay, in ticks
+	eventsUpFor   int // ticks the current stream has stayed up
 }
 
-func initialModel() model {
-	cli, err := NewDockerClient()
-	if err != nil {
-		return model{err: err}
-	}
+// modelOptions is what the model starts with: the config file's settings,
+// checked by main, and the Docker contexts.
+type modelOptions struct {
+	keys         keyMap
+	themes       []Theme
+	themeIndex   int // the theme to start with
+	alertRules   []alertRule
+	notifyRules  []notifyRule
+	execDefaults map[string]ExecOptions
+	savedFilters map[string]string
+	configPath   string
+	contexts     []dockerContext
+	contextName  string // the context to connect to
+	hosts        []hostConfig
+	stopTimeout  *int // seconds stop and restart wait; nil for the daemon's default
+}
 
-	return model{
-		dockerClient:       cli,
+func initialModel(o modelOptions) model {
+	m := model{
+		keys:               o.keys,
+		themes:             o.themes,
+		themeIndex:         o.themeIndex,
 		allContainers:      []Container{},
 		filteredContainers: []Container{},
 		cursor:             0,
@@ -87,16 +248,47 @@ func initialModel() model {
 		tableOffset:        0,
 		showStats:          false,
 		stats:              make(map[string]Stats),
+		statsHistory:       make(map[string]*statsRing),
+		alertRules:         o.alertRules,
+		alertHolds:         make(map[alertKey]*alertHold),
+		alerts:             make(map[string]alertLevel),
+		restartCounts:      make(map[string]int),
+		notifyRules:        o.notifyRules,
+		notifyLast:         make(map[notifyKey]time.Time),
+		selected:           make(map[string]bool),
+		shells:             make(map[string]*shellSession),
+		shellsStarting:     make(map[string]bool),
+		execDefaults:       o.execDefaults,
+		savedFilters:       o.savedFilters,
+		configPath:         o.configPath,
+		contexts:           o.contexts,
+		contextErrs:        make(map[string]error),
+		hostConfigs:        o.hosts,
+		stopTimeout:        o.stopTimeout,
+		groupByProject:     true,
+		collapsed:          make(map[string]bool),
 		activeView:         viewContainers,
+		eventsSession:      1,
+		eventsBackoff:      1,
 	}
+	m.useContext(o.contextName)
+	return m
 }
 
 // Init starts the Bubble Tea program.
-// It kicks off the tick loop and performs an initial container fetch.
+// It kicks off the tick loop, performs an initial container fetch and
+// subscribes to the daemon's event stream.
 func (m model) Init() tea.Cmd {
+	if m.hosts != nil {
+		return tea.Batch(waitForTick(), waitForAnimTick(), m.refreshContainers())
+	}
+	if m.dockerClient == nil {
+		return tea.Batch(waitForTick(), waitForAnimTick())
+	}
 	return tea.Batch(
 		waitForTick(),
 		waitForAnimTick(),
 		fetchContainers(m.dockerClient),
+		subscribeEvents(m.dockerClient, m.eventsSession),
 	)
 }
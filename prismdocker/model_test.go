@@ -0,0 +1,39 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNextSortOrder(t *testing.T) {
+	tests := []struct {
+		name      string
+		showStats bool
+		hosts     bool
+		want      []SortOrder // the cycle from ID
+	}{
+		{"plain", false, false, []SortOrder{SortByName, SortByImage, SortByState, SortByHealth, SortByID}},
+		{"stats mode", true, false, []SortOrder{SortByName, SortByImage, SortByState, SortByHealth, SortByCPU, SortByMem, SortByNet, SortByBlock, SortByID}},
+		{"hosts view", false, true, []SortOrder{SortByName, SortByImage, SortByState, SortByHealth, SortByHost, SortByID}},
+		{"hosts view in stats mode", true, true, []SortOrder{SortByName, SortByImage, SortByState, SortByHealth, SortByHost, SortByCPU, SortByMem, SortByNet, SortByBlock, SortByID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := model{showStats: tt.showStats}
+			if tt.hosts {
+				m.hosts = []*daemonHost{}
+			}
+			var got []SortOrder
+			for {
+				m.sortOrder = m.nextSortOrder()
+				got = append(got, m.sortOrder)
+				if m.sortOrder == SortByID || len(got) > int(SortByBlock)+1 {
+					break
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cycle = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,327 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/moby/moby/client"
+)
+
+// Network is one row of the networks table.
+type Network struct {
+	ID       string
+	Name     string
+	Driver   string
+	Scope    string
+	Subnet   string // first IPAM subnet, if any
+	Gateway  string
+	Internal bool
+}
+
+// ShortID is the 12 character network ID.
+func (n Network) ShortID() string {
+	if len(n.ID) > 12 {
+		return n.ID[:12]
+	}
+	return n.ID
+}
+
+// builtin reports whether n is one of the networks the daemon creates
+// itself and won't let us remove.
+func (n Network) builtin() bool {
+	switch n.Name {
+	case "bridge", "host", "none":
+		return true
+	}
+	return false
+}
+
+func ListNetworks(cli *client.Client) ([]Network, error) {
+	res, err := cli.NetworkList(context.Background(), client.NetworkListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var result []Network
+	for _, n := range res.Items {
+		net := Network{
+			ID:       n.ID,
+			Name:     n.Name,
+			Driver:   n.Driver,
+			Scope:    n.Scope,
+			Internal: n.Internal,
+		}
+		// Dual-stack networks list one config per family; show the first.
+		for _, cfg := range n.IPAM.Config {
+			if cfg.Subnet.IsValid() {
+				net.Subnet = cfg.Subnet.String()
+				if cfg.Gateway.IsValid() {
+					net.Gateway = cfg.Gateway.String()
+				}
+				break
+			}
+		}
+		result = append(result, net)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// CreateNetwork creates a network with the daemon's default driver.
+func CreateNetwork(cli *client.Client, name string) error {
+	_, err := cli.NetworkCreate(context.Background(), name, client.NetworkCreateOptions{})
+	return err
+}
+
+func RemoveNetwork(cli *client.Client, networkID string) error {
+	_, err := cli.NetworkRemove(context.Background(), networkID, client.NetworkRemoveOptions{})
+	return err
+}
+
+func ConnectNetwork(cli *client.Client, networkID, containerID string) error {
+	_, err := cli.NetworkConnect(context.Background(), networkID, client.NetworkConnectOptions{Container: containerID})
+	return err
+}
+
+func DisconnectNetwork(cli *client.Client, networkID, containerID string) error {
+	_, err := cli.NetworkDisconnect(context.Background(), networkID, client.NetworkDisconnectOptions{Container: containerID})
+	return err
+}
+
+// networkMember is a container attached to a network, with its address.
+type networkMember struct {
+	Container
+	IP string
+}
+
+// networkMembers maps each network name to the containers attached to it,
+// sorted by container name.
+func networkMembers(containers []Container) map[string][]networkMember {
+	members := make(map[string][]networkMember)
+	for _, c := range containers {
+		for name, ip := range c.Networks {
+			members[name] = append(members[name], networkMember{c, ip})
+		}
+	}
+	for _, list := range members {
+		sort.Slice(list, func(i, j int) bool { return list[i].Names < list[j].Names })
+	}
+	return members
+}
+
+type networksMsg struct {
+	cli      *client.Client
+	networks []Network
+	err      error
+}
+
+// networkActionMsg reports the result of a network action for the footer.
+type networkActionMsg struct {
+	cli  *client.Client
+	done string
+	err  error
+}
+
+func fetchNetworks(cli *client.Client) tea.Cmd {
+	return func() tea.Msg {
+		networks, err := ListNetworks(cli)
+		return networksMsg{cli, networks, err}
+	}
+}
+
+func doNetworkAction(cli *client.Client, done string, fn func(*client.Client) error) tea.Cmd {
+	return func() tea.Msg {
+		return networkActionMsg{cli, done, fn(cli)}
+	}
+}
+
+// ── Networks screen ────────────────────────────────────────────────────
+
+// networkPanelHeight is the height of the attached containers panel.
+const networkPanelHeight = 8
+
+// networkBodyHeight is the number of table rows that fit on screen.
+func (m model) networkBodyHeight() int {
+	// title + table header with its border, panel, footer with its top margin
+	h := m.height - 1 - 3 - networkPanelHeight - 2
+	if m.prompt != nil {
+		h--
+	}
+	if h < 1 {
+		h = 1
+	}
+	return h
+}
+
+func (m *model) moveNetworkCursor(delta int) {
+	m.networkList.move(delta, len(m.networks), m.networkBodyHeight())
+}
+
+func (m model) currentNetwork() (Network, bool) {
+	if m.networkList.cursor < len(m.networks) {
+		return m.networks[m.networkList.cursor], true
+	}
+	return Network{}, false
+}
+
+// networkTarget is the container that connect and disconnect act on: the
+// one under the cursor in the container list.
+func (m model) networkTarget() (Container, bool) {
+	return m.currentContainer()
+}
+
+// renderNetworksView renders the networks table and, below it, the
+// containers attached to the highlighted network.
+func (m model) renderNetworksView() string {
+	members := networkMembers(m.allContainers)
+
+	title := titleStyle.Render(fmt.Sprintf("Networks (%d)", len(m.networks)))
+	if c, ok := m.networkTarget(); ok {
+		title += "  " + inspectDimStyle.Render("Target: ") + inspectKeyStyle.Render(c.Names)
+	}
+	if m.networksErr != nil {
+		title += "  " + statusExitedStyle.Render("✖ "+m.networksErr.Error())
+	}
+
+	available := m.width - 10
+	if available < 40 {
+		available = 40
+	}
+	wID, wDriver, wScope, wUsed := 14, 10, 8, 12
+	rest := available - wID - wDriver - wScope - wUsed
+	if rest < 50 {
+		rest = 50
+	}
+	wName := rest * 40 / 100
+	wSubnet := rest * 35 / 100
+	wGateway := rest - wName - wSubnet
+
+	tHeader := tableStyle.Render(lipgloss.JoinHorizontal(lipgloss.Top,
+		ListItemStyle.Width(2).Render(""),
+		ListItemStyle.Width(wName).Render("Name"),
+		ListItemStyle.Width(wID).Render("ID"),
+		ListItemStyle.Width(wDriver).Render("Driver"),
+		ListItemStyle.Width(wScope).Render("Scope"),
+		ListItemStyle.Width(wSubnet).Render("Subnet"),
+		ListItemStyle.Width(wGateway).Render("Gateway"),
+		ListItemStyle.Width(wUsed).Render("Containers"),
+	))
+
+	bodyH := m.networkBodyHeight()
+	start, end := m.networkList.window(len(m.networks), bodyH)
+
+	var rows []string
+	if len(m.networks) == 0 {
+		rows = append(rows, "No networks found.")
+	}
+	for i := start; i < end; i++ {
+		n := m.networks[i]
+		selected := i == m.networkList.cursor
+		style := ListItemStyle
+		if selected {
+			style = selectedStyle
+		} else if i%2 == 0 {
+			style = style.Copy().Background(rowEvenBg) // Zebra stripe
+		}
+		cursor := "  "
+		if selected {
+			cursor = "> "
+		}
+		name := n.Name
+		if n.Internal {
+			name += " (internal)"
+		}
+		name = truncate(name, wName-2)
+		id := n.ShortID()
+		if !selected {
+			id = idStyle.Render(id)
+		}
+		subnet, gateway := n.Subnet, n.Gateway
+		if subnet == "" {
+			subnet = "-"
+		}
+		if gateway == "" {
+			gateway = "-"
+		}
+		used := "-"
+		if k := len(members[n.Name]); k > 0 {
+			used = fmt.Sprintf("%d", k)
+		}
+
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top,
+			style.Render(cursor),
+			style.Width(wName).Render(name),
+			style.Width(wID).Render(id),
+			style.Width(wDriver).Render(n.Driver),
+			style.Width(wScope).Render(n.Scope),
+			style.Width(wSubnet).Render(truncate(subnet, wSubnet-2)),
+			style.Width(wGateway).Render(truncate(gateway, wGateway-2)),
+			style.Width(wUsed).Render(used),
+		))
+	}
+	for len(rows) < bodyH {
+		rows = append(rows, "")
+	}
+
+	parts := []string{title, tHeader, lipgloss.JoinVertical(lipgloss.Left, rows...)}
+	parts = append(parts, m.renderNetworkPanel(members)...)
+	if m.prompt != nil {
+		parts = append(parts, m.prompt.render())
+	}
+
+	footerText := m.keys.footer("networks")
+	if m.statusMsg != "" {
+		footerText = m.statusMsg
+	}
+	parts = append(parts, helpStyle.Render(footerText))
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
+
+// renderNetworkPanel lists the containers attached to the highlighted
+// network with their addresses, padded to networkPanelHeight lines.
+func (m model) renderNetworkPanel(members map[string][]networkMember) []string {
+	var lines []string
+	if n, ok := m.currentNetwork(); ok {
+		list := members[n.Name]
+		lines = append(lines, inspectSectionStyle.Render(fmt.Sprintf("▌Attached to %s (%d)", n.Name, len(list))))
+		if len(list) == 0 {
+			lines = append(lines, "  "+inspectDimStyle.Render("(none)"))
+		}
+		max := networkPanelHeight - 1
+		for i, c := range list {
+			if i == max-1 && len(list) > max {
+				lines = append(lines, "  "+inspectDimStyle.Render(fmt.Sprintf("... and %d more", len(list)-i)))
+				break
+			}
+			ip := fmt.Sprintf("%-18s", c.IP)
+			if c.IP == "" {
+				ip = inspectDimStyle.Render(fmt.Sprintf("%-18s", "-"))
+			}
+			lines = append(lines, "  "+inspectKeyStyle.Render(fmt.Sprintf("%-30s", truncate(c.Names, 30)))+" "+
+				ip+" "+inspectDimStyle.Render(c.State))
+		}
+	}
+	for len(lines) < networkPanelHeight {
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// connectedTo reports whether c is attached to the network named name.
+func connectedTo(c Container, name string) bool {
+	_, ok := c.Networks[name]
+	return ok
+}
+
+// networkNames returns the sorted names of the networks c is attached to.
+func networkNames(c Container) string {
+	var names []string
+	for name := range c.Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
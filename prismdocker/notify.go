@@ -0,0 +1,404 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"runtime"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/moby/moby/api/types/events"
+	"github.com/moby/moby/client"
+)
+
+// NotifyRule is one notification rule from the config file: which events
+// of which containers to report, and how.
+type NotifyRule struct {
+	On      []string `json:"on"`      // exit, oom, unhealthy, alert; all of them if empty
+	Name    string   `json:"name"`    // glob the container name must match
+	Label   string   `json:"label"`   // label the container must have: "key" or "key=glob"
+	Project string   `json:"project"` // glob the compose project must match
+	Via     []string `json:"via"`     // bell, desktop, webhook; bell if empty
+	Webhook string   `json:"webhook"` // URL to POST to, needed with webhook
+	Every   string   `json:"every"`   // least time between two notifications of one event for one container; 1m if empty
+}
+
+// notifyEvent is something that can be notified about.
+type notifyEvent string
+
+const (
+	notifyExit      notifyEvent = "exit"      // the container died
+	notifyOOM       notifyEvent = "oom"       // the kernel killed a process for running out of memory
+	notifyUnhealthy notifyEvent = "unhealthy" // the healthcheck started failing
+	notifyAlert     notifyEvent = "alert"     // an alert rule started firing
+)
+
+var notifyEvents = []notifyEvent{notifyExit, notifyOOM, notifyUnhealthy, notifyAlert}
+
+const (
+	// defaultNotifyEvery is the per-rule repeat limit when none is set.
+	defaultNotifyEvery = time.Minute
+	// notifyBurst caps notifications across all rules per notifyWindow, so
+	// a host where everything falls over at once doesn't send a flood.
+	notifyBurst  = 10
+	notifyWindow = time.Minute
+	// webhookTimeout bounds one webhook request.
+	webhookTimeout = 5 * time.Second
+)
+
+// notifyRule is a validated NotifyRule.
+type notifyRule struct {
+	NotifyRule
+	on    map[notifyEvent]bool
+	every time.Duration
+}
+
+// loadNotifyRules validates the notification rules from the config file.
+// All problems are reported together.
+func loadNotifyRules(user []NotifyRule) ([]notifyRule, error) {
+	var rules []notifyRule
+	var errs []error
+	for i, r := range user {
+		rule, problems := compileNotifyRule(r)
+		for _, err := range problems {
+			errs = append(errs, fmt.Errorf("notifications[%d]: %w", i, err))
+		}
+		if len(problems) == 0 {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, errors.Join(errs...)
+}
+
+// compileNotifyRule checks a rule from the config and returns everything
+// wrong with it.
+func compileNotifyRule(r NotifyRule) (notifyRule, []error) {
+	rule := notifyRule{NotifyRule: r, on: make(map[notifyEvent]bool), every: defaultNotifyEvery}
+	var errs []error
+	for _, ev := range notifyEvents {
+		rule.on[ev] = len(r.On) == 0
+	}
+	for _, name := range r.On {
+		if _, ok := rule.on[notifyEvent(name)]; !ok {
+			errs = append(errs, fmt.Errorf("on: %q is not one of exit, oom, unhealthy, alert", name))
+			continue
+		}
+		rule.on[notifyEvent(name)] = true
+	}
+	if len(r.Via) == 0 {
+		rule.Via = []string{"bell"}
+	}
+	for _, via := range rule.Via {
+		switch via {
+		case "bell", "desktop":
+		case "webhook":
+			if u, err := url.Parse(r.Webhook); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				errs = append(errs, fmt.Errorf("webhook: %q is not an http(s) URL", r.Webhook))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("via: %q is not one of bell, desktop, webhook", via))
+		}
+	}
+	if r.Every != "" {
+		d, err := time.ParseDuration(r.Every)
+		if err != nil || d < 0 {
+			errs = append(errs, fmt.Errorf("every: %q is not a duration like 30s or 5m", r.Every))
+		}
+		rule.every = d
+	}
+	errs = append(errs, checkPatterns(r.Name, r.Label)...)
+	if _, err := path.Match(r.Project, ""); err != nil {
+		errs = append(errs, fmt.Errorf("project: %w", err))
+	}
+	return rule, errs
+}
+
+// matches reports whether the rule's patterns select c.
+func (r notifyRule) matches(c Container) bool {
+	if r.Project != "" {
+		if ok, _ := path.Match(r.Project, c.Project); !ok {
+			return false
+		}
+	}
+	return matchContainer(c, r.Name, r.Label)
+}
+
+// notifyKey identifies one event of one container under one rule, the unit
+// the repeat limit applies to.
+type notifyKey struct {
+	rule      int
+	container string // its key
+	event     notifyEvent
+}
+
+// notification is what gets delivered; webhooks receive it as JSON.
+type notification struct {
+	Event     notifyEvent `json:"event"`
+	Container string      `json:"container"`
+	ID        string      `json:"id"`
+	Host      string      `json:"host,omitempty"`
+	Project   string      `json:"project,omitempty"`
+	Message   string      `json:"message"`
+	Time      time.Time   `json:"time"`
+}
+
+// notifyDoneMsg reports a delivery that failed.
+type notifyDoneMsg struct{ err error }
+
+// notify hands an event to every rule that matches it, subject to each
+// rule's repeat limit and the overall burst limit.
+func (m *model) notify(ev notifyEvent, c Container, detail string, now time.Time) tea.Cmd {
+	name := c.Names
+	if c.Host != "" {
+		name = c.Host + "/" + name
+	}
+	n := notification{
+		Event:     ev,
+		Container: c.Names,
+		ID:        c.ID,
+		Host:      c.Host,
+		Project:   c.Project,
+		Message:   name + ": " + detail,
+		Time:      now,
+	}
+	// Forget deliveries that have left the burst window
+	for len(m.notifySent) > 0 && now.Sub(m.notifySent[0]) >= notifyWindow {
+		m.notifySent = m.notifySent[1:]
+	}
+
+	var cmds []tea.Cmd
+	for i, r := range m.notifyRules {
+		if !r.on[ev] || !r.matches(c) {
+			continue
+		}
+		key := notifyKey{i, c.key(), ev}
+		if last, ok := m.notifyLast[key]; ok && now.Sub(last) < r.every {
+			continue
+		}
+		if len(m.notifySent) >= notifyBurst {
+			m.statusMsg = "Notifications held back: too many in the last minute"
+			m.statusTick = 3
+			break
+		}
+		m.notifyLast[key] = now
+		m.notifySent = append(m.notifySent, now)
+		cmds = append(cmds, deliver(r, n, m.output))
+	}
+	return tea.Batch(cmds...)
+}
+
+// notifyContainerEvent turns a daemon event into a notification, for the
+// events rules can ask for.
+func (m *model) notifyContainerEvent(ev events.Message, id string) tea.Cmd {
+	c, ok := m.containerByKey(id)
+	if !ok {
+		// Gone from the list already; the event still names it
+		c = Container{ID: id, Names: ev.Actor.Attributes["name"], Labels: ev.Actor.Attributes}
+		c.Project = c.Labels[composeProjectLabel]
+	}
+	now := time.Now()
+	switch ev.Action {
+	case events.ActionDie:
+		return m.notify(notifyExit, c, "exited with code "+ev.Actor.Attributes["exitCode"], now)
+	case events.ActionOOM:
+		return m.notify(notifyOOM, c, "out of memory, a process was killed", now)
+	case events.ActionHealthStatusUnhealthy:
+		return m.notify(notifyUnhealthy, c, "healthcheck failing", now)
+	}
+	return nil
+}
+
+// notifyListChanges notifies about the exits and failing healthchecks
+// between two listings of one daemon, for when there is no event stream to
+// report them: while it is down and Prism polls, and in the hosts view.
+// Whether an exited container was OOM-killed takes an inspect to find out.
+func (m *model) notifyListChanges(cli *client.Client, old, containers []Container) tea.Cmd {
+	if len(m.notifyRules) == 0 || len(old) == 0 {
+		return nil
+	}
+	prev := make(map[string]Container, len(old))
+	for _, c := range old {
+		prev[c.ID] = c
+	}
+	now := time.Now()
+	var cmds []tea.Cmd
+	for _, c := range containers {
+		p, ok := prev[c.ID]
+		if !ok {
+			continue
+		}
+		if p.State == "running" && (c.State == "exited" || c.State == "dead" || c.State == "restarting") {
+			detail := "exited"
+			if code := statusExitCode(c.Status); code != "" {
+				detail += " with code " + code
+			}
+			cmds = append(cmds, m.notify(notifyExit, c, detail, now))
+			if m.notifyWants(notifyOOM) {
+				cmds = append(cmds, checkOOMKilled(cli, c))
+			}
+		}
+		if p.Health != "unhealthy" && c.Health == "unhealthy" {
+			cmds = append(cmds, m.notify(notifyUnhealthy, c, "healthcheck failing", now))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// notifyWants reports whether any rule reports ev.
+func (m model) notifyWants(ev notifyEvent) bool {
+	for _, r := range m.notifyRules {
+		if r.on[ev] {
+			return true
+		}
+	}
+	return false
+}
+
+// exitCodePattern finds the exit code in a status like "Exited (137) 5
+// seconds ago" or "Restarting (1) 2 seconds ago".
+var exitCodePattern = regexp.MustCompile(`\((-?\d+)\)`)
+
+// statusExitCode is the exit code in a container's status text, or "".
+func statusExitCode(status string) string {
+	if m := exitCodePattern.FindStringSubmatch(status); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// oomKilledMsg says a container seen to exit while polling was OOM-killed.
+type oomKilledMsg struct{ c Container }
+
+// checkOOMKilled inspects an exited container for whether it ran out of
+// memory, which the listing doesn't say.
+func checkOOMKilled(cli *client.Client, c Container) tea.Cmd {
+	return func() tea.Msg {
+		res, err := cli.ContainerInspect(context.Background(), c.ID, client.ContainerInspectOptions{})
+		if err != nil || res.Container.State == nil || !res.Container.State.OOMKilled {
+			return nil
+		}
+		return oomKilledMsg{c}
+	}
+}
+
+// containerByKey looks up a listed container. Outside the hosts view a
+// container's key is its ID.
+func (m model) containerByKey(key string) (Container, bool) {
+	for _, c := range m.allContainers {
+		if c.key() == key {
+			return c, true
+		}
+	}
+	return Container{}, false
+}
+
+// termOutput is the program's output. Bubble Tea writes every frame and
+// escape sequence in a single Write, so a bell rung under the same lock
+// lands between two of them rather than inside one.
+type termOutput struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+func newTermOutput(f *os.File) *termOutput {
+	return &termOutput{f: f}
+}
+
+func (o *termOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.f.Write(p)
+}
+
+// Read, Close and Fd let Bubble Tea see that the output is a terminal, so
+// it can ask for the window size.
+func (o *termOutput) Read(p []byte) (int, error) { return o.f.Read(p) }
+func (o *termOutput) Close() error               { return o.f.Close() }
+func (o *termOutput) Fd() uintptr                { return o.f.Fd() }
+
+// ring rings the terminal bell.
+func (o *termOutput) ring() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	_, err := io.WriteString(o.f, "\a")
+	return err
+}
+
+// deliver sends one notification every way the rule asks for. The bell
+// goes through out, which is nil when there is no terminal to ring.
+func deliver(r notifyRule, n notification, out *termOutput) tea.Cmd {
+	return func() tea.Msg {
+		var errs []error
+		for _, via := range r.Via {
+			switch via {
+			case "bell":
+				if out != nil {
+					errs = append(errs, out.ring())
+				}
+			case "desktop":
+				errs = append(errs, desktopNotify("Prism: "+string(n.Event), n.Message))
+			case "webhook":
+				errs = append(errs, postWebhook(r.Webhook, n))
+			}
+		}
+		return notifyDoneMsg{errors.Join(errs...)}
+	}
+}
+
+// desktopNotify shows a desktop notification: with notify-send, which talks
+// to the D-Bus notification service, or on macOS with osascript.
+func desktopNotify(title, body string) error {
+	if err := notifyCommand(runtime.GOOS, title, body).Run(); err != nil {
+		return fmt.Errorf("desktop notification: %w", err)
+	}
+	return nil
+}
+
+// notifyCommand builds the command desktopNotify runs on goos. osascript
+// gets title and body as arguments of its run handler rather than quoted into
+// the script, so no message can break out of its string literal.
+func notifyCommand(goos, title, body string) *exec.Cmd {
+	if goos == "darwin" {
+		return exec.Command("osascript",
+			"-e", "on run argv",
+			"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+			"-e", "end run",
+			title, body)
+	}
+	return exec.Command("notify-send", "--app-name=Prism", title, body)
+}
+
+// postWebhook POSTs the notification as JSON.
+func postWebhook(target string, n notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook: %s answered %s", req.URL.Host, resp.Status)
+	}
+	return nil
+}
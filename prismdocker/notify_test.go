@@ -0,0 +1,199 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestLoadNotifyRules(t *testing.T) {
+	tests := []struct {
+		name      string
+		rules     []NotifyRule
+		wantOn    [][]notifyEvent // per valid rule
+		wantVia   [][]string
+		wantEvery []time.Duration
+		wantErrs  []string
+	}{
+		{
+			name: "none",
+		},
+		{
+			name: "defaults: every event, the bell, once a minute",
+			rules: []NotifyRule{
+				{},
+			},
+			wantOn:    [][]notifyEvent{{notifyAlert, notifyExit, notifyOOM, notifyUnhealthy}},
+			wantVia:   [][]string{{"bell"}},
+			wantEvery: []time.Duration{time.Minute},
+		},
+		{
+			name: "explicit",
+			rules: []NotifyRule{
+				{On: []string{"exit", "oom"}, Via: []string{"desktop", "webhook"}, Webhook: "https://hooks.example.com/x", Every: "10m", Project: "shop", Name: "db-*", Label: "tier=back*"},
+			},
+			wantOn:    [][]notifyEvent{{notifyExit, notifyOOM}},
+			wantVia:   [][]string{{"desktop", "webhook"}},
+			wantEvery: []time.Duration{10 * time.Minute},
+		},
+		{
+			name: "every problem at once",
+			rules: []NotifyRule{
+				{On: []string{"crash"}, Via: []string{"pager", "webhook"}, Webhook: "ftp://example.com", Every: "often", Name: "[", Project: "["},
+			},
+			wantErrs: []string{
+				`notifications[0]: on: "crash" is not one of`,
+				`notifications[0]: via: "pager" is not one of`,
+				`notifications[0]: webhook: "ftp://example.com" is not an http(s) URL`,
+				`notifications[0]: every: "often"`,
+				"notifications[0]: name:",
+				"notifications[0]: project:",
+			},
+		},
+		{
+			name: "webhook without a URL; the good rule is kept",
+			rules: []NotifyRule{
+				{Via: []string{"webhook"}},
+				{On: []string{"unhealthy"}},
+			},
+			wantOn:    [][]notifyEvent{{notifyUnhealthy}},
+			wantVia:   [][]string{{"bell"}},
+			wantEvery: []time.Duration{time.Minute},
+			wantErrs:  []string{`notifications[0]: webhook: "" is not an http(s) URL`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := loadNotifyRules(tt.rules)
+			checkErrs(t, err, tt.wantErrs)
+			if len(rules) != len(tt.wantOn) {
+				t.Fatalf("got %d rules, want %d", len(rules), len(tt.wantOn))
+			}
+			for i, r := range rules {
+				var on []notifyEvent
+				for ev, ok := range r.on {
+					if ok {
+						on = append(on, ev)
+					}
+				}
+				sort.Slice(on, func(a, b int) bool { return on[a] < on[b] })
+				if !reflect.DeepEqual(on, tt.wantOn[i]) {
+					t.Errorf("rule %d: on = %v, want %v", i, on, tt.wantOn[i])
+				}
+				if !reflect.DeepEqual(r.Via, tt.wantVia[i]) {
+					t.Errorf("rule %d: via = %v, want %v", i, r.Via, tt.wantVia[i])
+				}
+				if r.every != tt.wantEvery[i] {
+					t.Errorf("rule %d: every = %v, want %v", i, r.every, tt.wantEvery[i])
+				}
+			}
+		})
+	}
+}
+
+func TestStatusExitCode(t *testing.T) {
+	tests := map[string]string{
+		"Exited (0) 5 seconds ago":      "0",
+		"Exited (137) 2 minutes ago":    "137",
+		"Restarting (1) 3 seconds ago":  "1",
+		"Exited (-1) About an hour ago": "-1",
+		"Up 3 hours":                    "",
+		"Created":                       "",
+	}
+	for status, want := range tests {
+		if got := statusExitCode(status); got != want {
+			t.Errorf("statusExitCode(%q) = %q, want %q", status, got, want)
+		}
+	}
+}
+
+func TestNotifyListChanges(t *testing.T) {
+	running := Container{ID: "a1", Names: "web", State: "running", Status: "Up 1 hour", Health: "healthy"}
+	tests := []struct {
+		name string
+		old  []Container
+		cur  []Container
+		want []notifyEvent
+	}{
+		{
+			name: "first listing says nothing",
+			cur:  []Container{{ID: "a1", State: "exited", Status: "Exited (1) now"}},
+		},
+		{
+			name: "unchanged",
+			old:  []Container{running},
+			cur:  []Container{running},
+		},
+		{
+			name: "exited",
+			old:  []Container{running},
+			cur:  []Container{{ID: "a1", Names: "web", State: "exited", Status: "Exited (1) 1 second ago"}},
+			want: []notifyEvent{notifyExit},
+		},
+		{
+			name: "crashed into a restart loop",
+			old:  []Container{running},
+			cur:  []Container{{ID: "a1", Names: "web", State: "restarting", Status: "Restarting (2) 1 second ago"}},
+			want: []notifyEvent{notifyExit},
+		},
+		{
+			name: "paused is not an exit",
+			old:  []Container{running},
+			cur:  []Container{{ID: "a1", Names: "web", State: "paused", Health: "healthy"}},
+		},
+		{
+			name: "turned unhealthy",
+			old:  []Container{running},
+			cur:  []Container{{ID: "a1", Names: "web", State: "running", Health: "unhealthy"}},
+			want: []notifyEvent{notifyUnhealthy},
+		},
+		{
+			name: "still unhealthy",
+			old:  []Container{{ID: "a1", State: "running", Health: "unhealthy"}},
+			cur:  []Container{{ID: "a1", State: "running", Health: "unhealthy"}},
+		},
+		{
+			name: "new container",
+			old:  []Container{running},
+			cur:  []Container{running, {ID: "b2", State: "exited", Status: "Exited (0) now"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := loadNotifyRules([]NotifyRule{{}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			m := model{notifyRules: rules, notifyLast: make(map[notifyKey]time.Time)}
+			m.notifyListChanges(nil, tt.old, tt.cur)
+			var got []notifyEvent
+			for key := range m.notifyLast {
+				got = append(got, key.event)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("notified %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotifyCommand(t *testing.T) {
+	title, body := `Prism: die`, `web "exited" \ code 1 é`
+	tests := []struct {
+		goos string
+		want []string
+	}{
+		{"linux", []string{"notify-send", "--app-name=Prism", title, body}},
+		{"darwin", []string{"osascript",
+			"-e", "on run argv",
+			"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+			"-e", "end run",
+			title, body}},
+	}
+	for _, tt := range tests {
+		if got := notifyCommand(tt.goos, title, body).Args; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: args = %q, want %q", tt.goos, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// picker is a popup list for actions that need one of a few fixed values,
+// such as the signal to send.
+type picker struct {
+	title    string
+	options  []pickerOption
+	cursor   int
+	onSelect func(m *model, value string) tea.Cmd
+}
+
+// pickerOption is one choice; hint is shown dimmed next to it.
+type pickerOption struct {
+	value string
+	hint  string
+}
+
+// openPicker shows the picker with the cursor on the first option.
+func (m *model) openPicker(title string, options []pickerOption, onSelect func(m *model, value string) tea.Cmd) {
+	m.picker = &picker{title: title, options: options, onSelect: onSelect}
+}
+
+// updatePicker handles a key while the picker is open. Like the text
+// prompt its keys are fixed: arrows or j/k move, Enter picks, Esc cancels.
+func (m *model) updatePicker(msg tea.KeyMsg) tea.Cmd {
+	p := m.picker
+	switch msg.String() {
+	case "up", "k":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case "down", "j":
+		if p.cursor < len(p.options)-1 {
+			p.cursor++
+		}
+	case "enter":
+		m.picker = nil
+		return p.onSelect(m, p.options[p.cursor].value)
+	case "esc", "q":
+		m.picker = nil
+	}
+	return nil
+}
+
+// render draws the picker as a centered popup.
+func (p *picker) render(width, height int) string {
+	lines := []string{titleStyle.Render(p.title), ""}
+	for i, o := range p.options {
+		line := "  " + o.value
+		if i == p.cursor {
+			line = "> " + o.value
+		}
+		line = lipgloss.NewStyle().Width(12).Render(line) + inspectDimStyle.Render(o.hint)
+		if i == p.cursor {
+			line = selectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "", mutedStyle.Render("↑/↓: Choose • Enter: OK • Esc: Cancel"))
+
+	popup := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(activeTheme.Accent)).
+		Padding(1, 3).
+		Render(strings.Join(lines, "\n"))
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, popup)
+}
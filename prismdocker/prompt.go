@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// textPrompt is a one-line input shown above the footer, used by actions
+// that need a value from the user such as a new tag or an image to pull.
+type textPrompt struct {
+	label    string
+	value    string
+	onSubmit func(m *model, value string) tea.Cmd
+}
+
+// openPrompt starts asking for a value. initial pre-fills the input.
+func (m *model) openPrompt(label, initial string, onSubmit func(m *model, value string) tea.Cmd) {
+	m.prompt = &textPrompt{label: label, value: initial, onSubmit: onSubmit}
+}
+
+// updatePrompt handles a key while the prompt has focus. Enter submits the
+// value, Esc cancels, everything else edits the input.
+func (m *model) updatePrompt(msg tea.KeyMsg) tea.Cmd {
+	p := m.prompt
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.prompt = nil
+		return p.onSubmit(m, p.value)
+	case tea.KeyEsc:
+		m.prompt = nil
+	case tea.KeyBackspace:
+		if r := []rune(p.value); len(r) > 0 {
+			p.value = string(r[:len(r)-1])
+		}
+	case tea.KeyCtrlU:
+		p.value = ""
+	case tea.KeySpace:
+		p.value += " "
+	case tea.KeyRunes:
+		p.value += string(msg.Runes)
+	}
+	return nil
+}
+
+func (p *textPrompt) render() string {
+	return infoStyle.Render(fmt.Sprintf("%s: %s█  (Enter: OK • Esc: Cancel)", p.label, p.value))
+}
@@ -0,0 +1,469 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hinshun/vt10x"
+	"github.com/moby/moby/client"
+)
+
+// shellSession is a shell running in a container through the exec API on
+// a pseudo-terminal. Its screen is kept by a terminal emulator, so the
+// session outlives the pane: detaching leaves the shell running and
+// reattaching shows it as it is now.
+type shellSession struct {
+	key        string         // the container's
+	cli        *client.Client // of the container's daemon
+	name       string
+	command    string // what runs, for the pane's title
+	execID     string
+	conn       client.HijackedResponse
+	term       vt10x.Terminal
+	cols, rows int
+	inputMu    sync.Mutex
+	pending    []byte        // keystrokes not yet written, in order; guarded by inputMu
+	input      chan struct{} // signalled, without blocking, when pending grows
+	output     chan struct{} // signalled, without blocking, after each read
+	done       chan struct{} // closed once the shell's output has ended
+	err        error         // why the output ended badly; read after done
+	exitCode   int           // the shell's exit code; read after done
+}
+
+// shellStartedMsg reports a session that has been created and attached,
+// or why the start in the container with this key failed.
+type shellStartedMsg struct {
+	key string
+	s   *shellSession
+	err error
+}
+
+// shellOutputMsg says a session's screen has changed.
+type shellOutputMsg struct{ s *shellSession }
+
+// shellEndedMsg says a session's shell has exited or its stream broke.
+type shellEndedMsg struct{ s *shellSession }
+
+// startShell creates an exec instance with a TTY of the given size in the
+// container and attaches to it. Without a command in the options the best
+// of the shellCandidates is looked for first.
+func startShell(cli *client.Client, c Container, o ExecOptions, cols, rows int) tea.Cmd {
+	return func() tea.Msg {
+		cmd, err := splitCommand(o.Command)
+		if err != nil {
+			return shellStartedMsg{key: c.key(), err: err}
+		}
+		if len(cmd) == 0 {
+			sh, err := detectShell(cli, c.ID)
+			if err != nil {
+				return shellStartedMsg{key: c.key(), err: err}
+			}
+			cmd = []string{sh}
+		}
+		ctx := context.Background()
+		size := client.ConsoleSize{Height: uint(rows), Width: uint(cols)}
+		created, err := cli.ExecCreate(ctx, c.ID, client.ExecCreateOptions{
+			Cmd:          cmd,
+			User:         o.User,
+			WorkingDir:   o.Workdir,
+			Env:          append([]string{"TERM=xterm"}, o.Env...),
+			TTY:          true,
+			ConsoleSize:  size,
+			AttachStdin:  true,
+			AttachStdout: true,
+			AttachStderr: true,
+		})
+		if err != nil {
+			return shellStartedMsg{key: c.key(), err: err}
+		}
+		att, err := cli.ExecAttach(ctx, created.ID, client.ExecAttachOptions{TTY: true, ConsoleSize: size})
+		if err != nil {
+			return shellStartedMsg{key: c.key(), err: err}
+		}
+		s := &shellSession{
+			key:     c.key(),
+			cli:     cli,
+			name:    c.Names,
+			command: strings.Join(cmd, " "),
+			execID:  created.ID,
+			conn:    att.HijackedResponse,
+			cols:    cols,
+			rows:    rows,
+			input:   make(chan struct{}, 1),
+			output:  make(chan struct{}, 1),
+			done:    make(chan struct{}),
+		}
+		// Replies to the shell's queries (cursor position and the like)
+		// go back to it like keystrokes
+		s.term = vt10x.New(vt10x.WithSize(cols, rows), vt10x.WithWriter(shellReplies{s}))
+		go s.read()
+		go s.write()
+		return shellStartedMsg{key: c.key(), s: s}
+	}
+}
+
+// read feeds the shell's output to the terminal emulator until it ends,
+// then picks up the exit code. With a TTY the stream is raw, not
+// multiplexed like the log streams.
+func (s *shellSession) read() {
+	defer close(s.done)
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := s.conn.Reader.Read(buf)
+		if n > 0 {
+			s.term.Write(buf[:n])
+			select {
+			case s.output <- struct{}{}:
+			default:
+			}
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				s.err = err
+				return
+			}
+			s.exitCode, s.err = execExitCode(s.cli, s.execID)
+			return
+		}
+	}
+}
+
+// write sends keystrokes to the shell in the order they were typed,
+// whatever has queued up since the last write in one go.
+func (s *shellSession) write() {
+	for {
+		select {
+		case <-s.input:
+			s.inputMu.Lock()
+			b := s.pending
+			s.pending = nil
+			s.inputMu.Unlock()
+			if _, err := s.conn.Conn.Write(b); err != nil {
+				return
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// send queues input for the shell. The queue grows as needed, so a large
+// paste arrives whole without the UI ever waiting on the shell.
+func (s *shellSession) send(b []byte) {
+	if len(b) == 0 {
+		return
+	}
+	s.inputMu.Lock()
+	s.pending = append(s.pending, b...)
+	s.inputMu.Unlock()
+	select {
+	case s.input <- struct{}{}:
+	default:
+	}
+}
+
+// Close hangs up on the shell.
+func (s *shellSession) Close() {
+	s.conn.Close()
+}
+
+// shellReplies hands what the terminal emulator writes to the shell. It
+// writes while holding its lock, so this must not block.
+type shellReplies struct{ s *shellSession }
+
+func (w shellReplies) Write(p []byte) (int, error) {
+	w.s.send(p)
+	return len(p), nil
+}
+
+// waitForShellOutput blocks until the session's screen changes or its
+// shell ends.
+func waitForShellOutput(s *shellSession) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case <-s.output:
+			return shellOutputMsg{s}
+		case <-s.done:
+			return shellEndedMsg{s}
+		}
+	}
+}
+
+// resizeShell tells the daemon about a new terminal size. If it fails the
+// shell keeps drawing for the old size until the next resize.
+func resizeShell(s *shellSession) tea.Cmd {
+	cols, rows := s.cols, s.rows
+	return func() tea.Msg {
+		s.cli.ExecResize(context.Background(), s.execID, client.ExecResizeOptions{Height: uint(rows), Width: uint(cols)})
+		return nil
+	}
+}
+
+// shellKeyBytes encodes a key press the way a terminal would send it.
+// appCursor is the application cursor mode full-screen programs switch on,
+// in which the arrow keys send SS3 sequences instead of CSI ones.
+func shellKeyBytes(k tea.KeyMsg, appCursor bool) []byte {
+	arrow := func(c byte) []byte {
+		if appCursor {
+			return []byte{0x1b, 'O', c}
+		}
+		return []byte{0x1b, '[', c}
+	}
+	var b []byte
+	switch k.Type {
+	case tea.KeyRunes:
+		b = []byte(string(k.Runes))
+	case tea.KeySpace:
+		b = []byte{' '}
+	case tea.KeyUp:
+		b = arrow('A')
+	case tea.KeyDown:
+		b = arrow('B')
+	case tea.KeyRight:
+		b = arrow('C')
+	case tea.KeyLeft:
+		b = arrow('D')
+	case tea.KeyHome:
+		b = arrow('H')
+	case tea.KeyEnd:
+		b = arrow('F')
+	case tea.KeyShiftTab:
+		b = []byte("\x1b[Z")
+	case tea.KeyInsert:
+		b = []byte("\x1b[2~")
+	case tea.KeyDelete:
+		b = []byte("\x1b[3~")
+	case tea.KeyPgUp:
+		b = []byte("\x1b[5~")
+	case tea.KeyPgDown:
+		b = []byte("\x1b[6~")
+	case tea.KeyF1, tea.KeyF2, tea.KeyF3, tea.KeyF4:
+		b = []byte{0x1b, 'O', 'P' + byte(tea.KeyF1-k.Type)}
+	case tea.KeyF5, tea.KeyF6, tea.KeyF7, tea.KeyF8, tea.KeyF9, tea.KeyF10, tea.KeyF11, tea.KeyF12:
+		codes := []int{15, 17, 18, 19, 20, 21, 23, 24}
+		b = []byte(fmt.Sprintf("\x1b[%d~", codes[tea.KeyF5-k.Type]))
+	default:
+		// Control characters, Enter, Tab, Esc and Backspace are their own
+		// byte
+		if k.Type >= 0 && k.Type < 32 || k.Type == tea.KeyBackspace {
+			b = []byte{byte(k.Type)}
+		}
+	}
+	if k.Alt && len(b) > 0 {
+		b = append([]byte{0x1b}, b...)
+	}
+	return b
+}
+
+// ── Shell pane ─────────────────────────────────────────────────────────
+
+// shellPaneWidth is the width of the pane beside the container list,
+// border included.
+func (m model) shellPaneWidth() int {
+	return max(m.width/2, 40)
+}
+
+// shellSize is the terminal size that fits in the pane.
+func (m model) shellSize() (cols, rows int) {
+	// left border and gap; title, box border and footer with its margin
+	cols = m.shellPaneWidth() - 3
+	rows = m.height - 1 - 2 - 2
+	return max(cols, 10), max(rows, 2)
+}
+
+// execShell opens the shell pane for the container under the cursor:
+// back onto its running session if it has one, otherwise in a new one
+// with its image's exec defaults.
+func (m *model) execShell() tea.Cmd {
+	c, ok := m.currentContainer()
+	if !ok || c.State != "running" {
+		return nil
+	}
+	if s, ok := m.shells[c.key()]; ok {
+		return m.attachShell(s)
+	}
+	return m.startShellWith(c, m.execDefaultsFor(c))
+}
+
+// startShellWith starts a new session in c, unless it has one or one is
+// already on its way; a second press while the shell is looked for would
+// otherwise start a second session.
+func (m *model) startShellWith(c Container, o ExecOptions) tea.Cmd {
+	if s, ok := m.shells[c.key()]; ok {
+		return m.attachShell(s)
+	}
+	if m.shellsStarting[c.key()] {
+		m.statusMsg = "Still starting a shell in " + c.Names + "..."
+		m.statusTick = 3
+		return nil
+	}
+	m.shellsStarting[c.key()] = true
+	m.statusMsg = "Starting a shell in " + c.Names + "..."
+	m.statusTick = 3
+	cols, rows := m.shellSize()
+	return startShell(m.clientFor(c), c, o, cols, rows)
+}
+
+// attachShell shows a session in the pane, sized to fit it.
+func (m *model) attachShell(s *shellSession) tea.Cmd {
+	m.activeView = viewShell
+	m.shellKey = s.key
+	return m.fitShell()
+}
+
+// fitShell resizes the session in the pane to the pane's current size.
+func (m *model) fitShell() tea.Cmd {
+	s, ok := m.shells[m.shellKey]
+	if !ok || m.activeView != viewShell {
+		return nil
+	}
+	cols, rows := m.shellSize()
+	if cols == s.cols && rows == s.rows {
+		return nil
+	}
+	s.cols, s.rows = cols, rows
+	s.term.Resize(cols, rows)
+	return resizeShell(s)
+}
+
+// updateShell handles a key press while the pane has the focus: the
+// detach key goes back to the list, everything else goes to the shell.
+func (m *model) updateShell(msg tea.KeyMsg) tea.Cmd {
+	s, ok := m.shells[m.shellKey]
+	if !ok {
+		m.activeView = viewContainers
+		return nil
+	}
+	if m.keys.action("shell", msg.String()) == "detach" {
+		m.activeView = viewContainers
+		m.statusMsg = fmt.Sprintf("Detached from the shell in %s; %s reattaches", s.name, m.keys.keysFor("containers", "shell"))
+		m.statusTick = 3
+		return nil
+	}
+	s.send(shellKeyBytes(msg, s.term.Mode()&vt10x.ModeAppCursor != 0))
+	return nil
+}
+
+// endShell forgets a session whose shell has exited.
+func (m *model) endShell(s *shellSession) {
+	if m.shells[s.key] != s {
+		return
+	}
+	s.Close()
+	delete(m.shells, s.key)
+	if m.activeView == viewShell && m.shellKey == s.key {
+		m.activeView = viewContainers
+	}
+	m.statusMsg = "Shell in " + s.name + " ended"
+	switch {
+	case s.err != nil:
+		m.statusMsg += ": " + s.err.Error()
+	case s.exitCode == 126 || s.exitCode == 127:
+		// The runtime's own codes for a command it couldn't start
+		m.statusMsg = fmt.Sprintf("Couldn't run %q in %s (exit status %d)", s.command, s.name, s.exitCode)
+	case s.exitCode != 0:
+		m.statusMsg += fmt.Sprintf(" with exit status %d", s.exitCode)
+	}
+	m.statusTick = 3
+}
+
+// Glyph attribute bits of vt10x, which it doesn't export.
+const (
+	glyphReverse   = 1 << 0
+	glyphUnderline = 1 << 1
+	glyphBold      = 1 << 2
+	glyphItalic    = 1 << 4
+)
+
+// glyphColor converts an emulator colour: the 256 palette entries by
+// number, anything above as 24-bit RGB.
+func glyphColor(c vt10x.Color) (lipgloss.TerminalColor, bool) {
+	switch {
+	case c == vt10x.DefaultFG || c == vt10x.DefaultBG || c == vt10x.DefaultCursor:
+		return nil, false
+	case c < 256:
+		return lipgloss.Color(strconv.Itoa(int(c))), true
+	default:
+		return lipgloss.Color(fmt.Sprintf("#%06x", uint32(c)&0xffffff)), true
+	}
+}
+
+// glyphStyle is the style a cell is drawn with.
+func glyphStyle(g vt10x.Glyph, cursor bool) lipgloss.Style {
+	st := lipgloss.NewStyle()
+	if fg, ok := glyphColor(g.FG); ok {
+		st = st.Foreground(fg)
+	}
+	if bg, ok := glyphColor(g.BG); ok {
+		st = st.Background(bg)
+	}
+	return st.
+		Bold(g.Mode&glyphBold != 0).
+		Italic(g.Mode&glyphItalic != 0).
+		Underline(g.Mode&glyphUnderline != 0).
+		Reverse((g.Mode&glyphReverse != 0) != cursor)
+}
+
+// renderTerminal draws the emulator's screen, cell runs with the same
+// attributes rendered together.
+func renderTerminal(t vt10x.Terminal) string {
+	t.Lock()
+	defer t.Unlock()
+	cols, rows := t.Size()
+	cur := t.Cursor()
+	showCursor := t.CursorVisible()
+
+	lines := make([]string, rows)
+	for y := range rows {
+		var line, run strings.Builder
+		var runGlyph vt10x.Glyph
+		var runCursor bool
+		flush := func() {
+			if run.Len() > 0 {
+				line.WriteString(glyphStyle(runGlyph, runCursor).Render(run.String()))
+				run.Reset()
+			}
+		}
+		for x := range cols {
+			g := t.Cell(x, y)
+			isCursor := showCursor && x == cur.X && y == cur.Y
+			if x == 0 || g.FG != runGlyph.FG || g.BG != runGlyph.BG || g.Mode != runGlyph.Mode || isCursor != runCursor {
+				flush()
+				runGlyph, runCursor = g, isCursor
+			}
+			if g.Char == 0 {
+				g.Char = ' '
+			}
+			run.WriteRune(g.Char)
+		}
+		flush()
+		lines[y] = line.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderShellView renders the container list with the shell pane beside it.
+func (m model) renderShellView() string {
+	paneW := m.shellPaneWidth()
+	list := m
+	list.width = max(m.width-paneW, 0)
+	left := lipgloss.NewStyle().MaxWidth(list.width).Render(list.renderContainersView())
+
+	s, ok := m.shells[m.shellKey]
+	if !ok {
+		return left
+	}
+	title := titleStyle.Render(truncate(fmt.Sprintf("Shell: %s (%s)", s.name, s.command), paneW-1))
+	box := tableStyle.Copy().BorderForeground(lipgloss.Color(activeTheme.Accent)).Render(renderTerminal(s.term))
+	footer := helpStyle.Render(m.keys.footer("shell"))
+	pane := lipgloss.NewStyle().PaddingLeft(1).Render(lipgloss.JoinVertical(lipgloss.Left, title, box, footer))
+
+	return lipgloss.JoinHorizontal(lipgloss.Top, left, pane)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestShellKeyBytes(t *testing.T) {
+	tests := []struct {
+		name      string
+		key       tea.KeyMsg
+		appCursor bool
+		want      string
+	}{
+		{"runes", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("hé")}, false, "hé"},
+		{"space", tea.KeyMsg{Type: tea.KeySpace}, false, " "},
+		{"up", tea.KeyMsg{Type: tea.KeyUp}, false, "\x1b[A"},
+		{"up, app cursor", tea.KeyMsg{Type: tea.KeyUp}, true, "\x1bOA"},
+		{"left, app cursor", tea.KeyMsg{Type: tea.KeyLeft}, true, "\x1bOD"},
+		{"home", tea.KeyMsg{Type: tea.KeyHome}, false, "\x1b[H"},
+		{"end, app cursor", tea.KeyMsg{Type: tea.KeyEnd}, true, "\x1bOF"},
+		{"shift+tab", tea.KeyMsg{Type: tea.KeyShiftTab}, false, "\x1b[Z"},
+		{"delete", tea.KeyMsg{Type: tea.KeyDelete}, false, "\x1b[3~"},
+		{"page down", tea.KeyMsg{Type: tea.KeyPgDown}, true, "\x1b[6~"},
+		{"f1", tea.KeyMsg{Type: tea.KeyF1}, false, "\x1bOP"},
+		{"f4", tea.KeyMsg{Type: tea.KeyF4}, false, "\x1bOS"},
+		{"f5", tea.KeyMsg{Type: tea.KeyF5}, false, "\x1b[15~"},
+		{"f6", tea.KeyMsg{Type: tea.KeyF6}, false, "\x1b[17~"},
+		{"f10", tea.KeyMsg{Type: tea.KeyF10}, false, "\x1b[21~"},
+		{"f11", tea.KeyMsg{Type: tea.KeyF11}, false, "\x1b[23~"},
+		{"f12", tea.KeyMsg{Type: tea.KeyF12}, false, "\x1b[24~"},
+		{"enter", tea.KeyMsg{Type: tea.KeyEnter}, false, "\r"},
+		{"tab", tea.KeyMsg{Type: tea.KeyTab}, false, "\t"},
+		{"esc", tea.KeyMsg{Type: tea.KeyEsc}, false, "\x1b"},
+		{"ctrl+c", tea.KeyMsg{Type: tea.KeyCtrlC}, false, "\x03"},
+		{"ctrl+d", tea.KeyMsg{Type: tea.KeyCtrlD}, false, "\x04"},
+		{"backspace", tea.KeyMsg{Type: tea.KeyBackspace}, false, "\x7f"},
+		{"alt+b", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b"), Alt: true}, false, "\x1bb"},
+		{"alt+backspace", tea.KeyMsg{Type: tea.KeyBackspace, Alt: true}, false, "\x1b\x7f"},
+		{"alt+up", tea.KeyMsg{Type: tea.KeyUp, Alt: true}, false, "\x1b\x1b[A"},
+		{"f13 has no encoding", tea.KeyMsg{Type: tea.KeyF13}, false, ""},
+		{"alt alone sends nothing", tea.KeyMsg{Type: tea.KeyF13, Alt: true}, false, ""},
+	}
+	for _, tt := range tests {
+		if got := string(shellKeyBytes(tt.key, tt.appCursor)); got != tt.want {
+			t.Errorf("%s: shellKeyBytes = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,343 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// statsHistorySize is how many samples are kept per container: five
+// minutes at one sample per two-second tick.
+const statsHistorySize = 150
+
+// sparkWidth is the number of samples shown by an inline sparkline.
+const sparkWidth = 8
+
+// statsRing holds the most recent samples of one container, oldest first.
+type statsRing struct {
+	samples [statsHistorySize]Stats
+	start   int
+	n       int
+}
+
+// push appends a sample, dropping the oldest once the ring is full.
+// Samples the daemon took at the same instant as the last one are ignored.
+func (r *statsRing) push(s Stats) {
+	if r.n > 0 && !s.Read.IsZero() && s.Read.Equal(r.at(r.n-1).Read) {
+		return
+	}
+	if r.n < statsHistorySize {
+		r.samples[(r.start+r.n)%statsHistorySize] = s
+		r.n++
+		return
+	}
+	r.samples[r.start] = s
+	r.start = (r.start + 1) % statsHistorySize
+}
+
+// at returns the i-th sample, 0 being the oldest.
+func (r *statsRing) at(i int) Stats {
+	return r.samples[(r.start+i)%statsHistorySize]
+}
+
+// span is the time covered by the samples.
+func (r *statsRing) span() time.Duration {
+	if r == nil || r.n < 2 {
+		return 0
+	}
+	return r.at(r.n - 1).Read.Sub(r.at(0).Read)
+}
+
+// series returns one value per sample.
+func (r *statsRing) series(f func(Stats) float64) []float64 {
+	if r == nil {
+		return nil
+	}
+	values := make([]float64, r.n)
+	for i := range values {
+		values[i] = f(r.at(i))
+	}
+	return values
+}
+
+// withRates fills in the per-second rates of s from the previous sample of
+// the same container. A counter that went backwards (the container
+// restarted) gives a rate of zero.
+func (s Stats) withRates(prev Stats) Stats {
+	dt := s.Read.Sub(prev.Read).Seconds()
+	if prev.Read.IsZero() || dt <= 0 {
+		return s
+	}
+	rate := func(cur, old float64) float64 {
+		if cur < old {
+			return 0
+		}
+		return (cur - old) / dt
+	}
+	s.NetRxRate = rate(s.NetRx, prev.NetRx)
+	s.NetTxRate = rate(s.NetTx, prev.NetTx)
+	s.BlkReadRate = rate(s.BlkRead, prev.BlkRead)
+	s.BlkWriteRate = rate(s.BlkWrite, prev.BlkWrite)
+	for name, n := range s.Interfaces {
+		if old, ok := prev.Interfaces[name]; ok {
+			n.RxRate = rate(n.Rx, old.Rx)
+			n.TxRate = rate(n.Tx, old.Tx)
+			s.Interfaces[name] = n
+		}
+	}
+	return s
+}
+
+// recordStats adds a round of samples to the per-container history.
+func (m *model) recordStats(stats map[string]Stats) {
+	now := time.Now()
+	for key, s := range stats {
+		if s.Read.IsZero() {
+			s.Read = now
+		}
+		r := m.statsHistory[key]
+		if r == nil {
+			r = &statsRing{}
+			m.statsHistory[key] = r
+		}
+		r.push(s)
+	}
+}
+
+// sparkBlocks are the eight heights of a sparkline cell, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws the last width values as a row of block characters
+// scaled between lo and hi. Missing history is left blank on the left so
+// the newest sample is always in the last cell.
+func sparkline(values []float64, width int, lo, hi float64) string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", width-len(values)))
+	for _, v := range values {
+		level := 0
+		if hi > lo {
+			level = int((v - lo) / (hi - lo) * float64(len(sparkBlocks)-1))
+		}
+		level = max(0, min(level, len(sparkBlocks)-1))
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
+
+// seriesRange returns the smallest and largest value.
+func seriesRange(values []float64) (lo, hi float64) {
+	for i, v := range values {
+		if i == 0 || v < lo {
+			lo = v
+		}
+		if i == 0 || v > hi {
+			hi = v
+		}
+	}
+	return lo, hi
+}
+
+// renderChart plots the last width values as a bar chart height rows tall,
+// with eighth-block resolution, scaled from 0 to hi.
+func renderChart(values []float64, width, height int, hi float64) []string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	pad := width - len(values)
+	lines := make([]string, height)
+	for row := range lines {
+		var b strings.Builder
+		b.WriteString(strings.Repeat(" ", pad))
+		floor := (height - 1 - row) * 8 // eighths below this row
+		for _, v := range values {
+			eighths := 0
+			if hi > 0 {
+				eighths = int(v / hi * float64(height*8))
+			}
+			switch fill := eighths - floor; {
+			case fill <= 0:
+				b.WriteRune(' ')
+			case fill >= 8:
+				b.WriteRune('█')
+			default:
+				b.WriteRune(sparkBlocks[fill-1])
+			}
+		}
+		lines[row] = b.String()
+	}
+	return lines
+}
+
+// chartSeries is one plotted line of a stats panel.
+type chartSeries struct {
+	label  string
+	values []float64
+	format func(float64) string
+	color  string
+}
+
+// formatPercent and formatRate label chart values.
+func formatPercent(v float64) string { return fmt.Sprintf("%.1f%%", v) }
+func formatRate(v float64) string    { return formatBytes(v) + "/s" }
+
+// graphTarget returns the container shown on the stats screen.
+func (m model) graphTarget() (Container, bool) {
+	for _, c := range m.allContainers {
+		if c.key() == m.graphKey {
+			return c, true
+		}
+	}
+	return Container{}, false
+}
+
+// moveGraphTarget switches the stats screen to the previous or next
+// container in the list.
+func (m *model) moveGraphTarget(delta int) {
+	n := len(m.filteredContainers)
+	if n == 0 {
+		return
+	}
+	i := 0
+	for j, c := range m.filteredContainers {
+		if c.key() == m.graphKey {
+			i = (j + delta + n) % n
+			break
+		}
+	}
+	m.graphKey = m.filteredContainers[i].key()
+}
+
+// renderStatsView renders the history graphs of one container.
+func (m model) renderStatsView() string {
+	c, _ := m.graphTarget()
+	hist := m.statsHistory[m.graphKey]
+
+	title := titleStyle.Render("Stats: " + c.Names)
+	switch c.State {
+	case "running":
+	case "paused":
+		title += "  " + statusPausedStyle.Render("● "+c.State)
+	default:
+		title += "  " + statusExitedStyle.Render("● "+c.State)
+	}
+	span := "collecting…"
+	if d := hist.span(); d > 0 {
+		span = "last " + d.Round(time.Second).String()
+	}
+	title += "  " + infoStyle.Render(span)
+
+	footer := helpStyle.Render(m.keys.footer("stats"))
+
+	// The chart follows the working set, the number alerts are based on;
+	// the title breaks the latest sample down
+	mem := hist.series(func(s Stats) float64 { return s.MemUsage })
+	var memNote string
+	if hist != nil && hist.n > 0 {
+		last := hist.at(hist.n - 1)
+		memNote = fmt.Sprintf("RSS %s · cache %s", formatBytes(last.MemRSS), formatBytes(last.MemCache))
+		if last.MemLimit > 0 {
+			memNote += " · limit " + formatBytes(last.MemLimit)
+		}
+		if last.MemCgroup > 0 {
+			memNote += fmt.Sprintf(" · cgroup v%d", last.MemCgroup)
+		}
+	}
+	// Each interface's traffic goes under the network charts
+	var ifaces []string
+	if hist != nil && hist.n > 0 {
+		last := hist.at(hist.n - 1)
+		for _, name := range sortedKeys(last.Interfaces) {
+			n := last.Interfaces[name]
+			ifaces = append(ifaces, fmt.Sprintf("%s  ↓ %-12s ↑ %-12s  total ↓ %s  ↑ %s",
+				inspectKeyStyle.Render(fmt.Sprintf("%-10s", name)),
+				formatRate(n.RxRate), formatRate(n.TxRate), formatBytes(n.Rx), formatBytes(n.Tx)))
+		}
+	}
+	panels := []struct {
+		title  string
+		note   string
+		series []chartSeries
+		extra  []string
+	}{
+		{"CPU", "", []chartSeries{
+			{"", hist.series(func(s Stats) float64 { return s.CPUPercent }), formatPercent, activeTheme.Info},
+		}, nil},
+		{"Memory working set", memNote, []chartSeries{
+			{"", mem, formatBytes, activeTheme.Accent},
+		}, nil},
+		{"Network", "", []chartSeries{
+			{"RX", hist.series(func(s Stats) float64 { return s.NetRxRate }), formatRate, activeTheme.PortHost},
+			{"TX", hist.series(func(s Stats) float64 { return s.NetTxRate }), formatRate, activeTheme.PortContainer},
+		}, ifaces},
+		{"Block I/O", "", []chartSeries{
+			{"Read", hist.series(func(s Stats) float64 { return s.BlkReadRate }), formatRate, activeTheme.Good},
+			{"Write", hist.series(func(s Stats) float64 { return s.BlkWriteRate }), formatRate, activeTheme.Warn},
+		}, nil},
+	}
+
+	// Title + blank line, footer with its margin, and per panel a heading,
+	// a caption line and its extra lines
+	chartH := (m.height - 2 - 2 - 2*len(panels) - len(ifaces)) / len(panels)
+	chartH = max(chartH, 2)
+
+	var body []string
+	for _, p := range panels {
+		body = append(body, inspectSectionStyle.Render(p.title)+"   "+inspectDimStyle.Render(p.note))
+		const gap = 4
+		w := (m.width - gap*(len(p.series)-1)) / len(p.series)
+		var cols []string
+		for i, s := range p.series {
+			if i > 0 {
+				cols = append(cols, strings.Repeat(" ", gap))
+			}
+			cols = append(cols, renderChartPanel(s, w, chartH))
+		}
+		body = append(body, lipgloss.JoinHorizontal(lipgloss.Top, cols...))
+		body = append(body, p.extra...)
+	}
+	return title + "\n\n" + strings.Join(body, "\n") + "\n" + footer
+}
+
+// chartAxisWidth is the space left of a chart for its scale labels.
+const chartAxisWidth = 13
+
+// renderChartPanel draws one series: a caption with its current, peak and
+// average values, then the chart with its scale on the left.
+func renderChartPanel(s chartSeries, width, height int) string {
+	var caption string
+	if s.label != "" {
+		caption = s.label + "  "
+	}
+	if len(s.values) == 0 {
+		caption += "no samples yet"
+	} else {
+		_, hi := seriesRange(s.values)
+		var sum float64
+		for _, v := range s.values {
+			sum += v
+		}
+		caption += fmt.Sprintf("now %s · max %s · avg %s",
+			s.format(s.values[len(s.values)-1]), s.format(hi), s.format(sum/float64(len(s.values))))
+	}
+
+	_, hi := seriesRange(s.values)
+	chart := renderChart(s.values, max(width-chartAxisWidth, 1), height, hi)
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color(s.color))
+	lines := []string{inspectKeyStyle.Render(caption)}
+	for i, l := range chart {
+		var axis string
+		switch i {
+		case 0:
+			axis = s.format(hi)
+		case height - 1:
+			axis = s.format(0)
+		}
+		lines = append(lines, inspectDimStyle.Render(fmt.Sprintf("%*s ┤", chartAxisWidth-2, axis))+style.Render(l))
+	}
+	return strings.Join(lines, "\n")
+}
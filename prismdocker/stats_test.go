@@ -0,0 +1,189 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestStatsRing(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(i int) time.Time { return t0.Add(time.Duration(i) * 2 * time.Second) }
+	cpu := func(s Stats) float64 { return s.CPUPercent }
+
+	tests := []struct {
+		name     string
+		push     []Stats
+		wantCPU  []float64
+		wantSpan time.Duration
+	}{
+		{
+			name:    "empty",
+			wantCPU: []float64{},
+		},
+		{
+			name:    "one sample has no span",
+			push:    []Stats{{Read: at(0), CPUPercent: 1}},
+			wantCPU: []float64{1},
+		},
+		{
+			name:     "oldest first",
+			push:     []Stats{{Read: at(0), CPUPercent: 1}, {Read: at(1), CPUPercent: 2}, {Read: at(2), CPUPercent: 3}},
+			wantCPU:  []float64{1, 2, 3},
+			wantSpan: 4 * time.Second,
+		},
+		{
+			name:     "a repeated sample is dropped",
+			push:     []Stats{{Read: at(0), CPUPercent: 1}, {Read: at(0), CPUPercent: 9}, {Read: at(1), CPUPercent: 2}},
+			wantCPU:  []float64{1, 2},
+			wantSpan: 2 * time.Second,
+		},
+		{
+			name:    "samples without a time are all kept",
+			push:    []Stats{{CPUPercent: 1}, {CPUPercent: 2}},
+			wantCPU: []float64{1, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &statsRing{}
+			for _, s := range tt.push {
+				r.push(s)
+			}
+			if got := r.series(cpu); !reflect.DeepEqual(got, tt.wantCPU) {
+				t.Errorf("series = %v, want %v", got, tt.wantCPU)
+			}
+			if got := r.span(); got != tt.wantSpan {
+				t.Errorf("span = %v, want %v", got, tt.wantSpan)
+			}
+		})
+	}
+
+	t.Run("full ring drops the oldest", func(t *testing.T) {
+		r := &statsRing{}
+		for i := range statsHistorySize + 5 {
+			r.push(Stats{Read: at(i), CPUPercent: float64(i)})
+		}
+		got := r.series(cpu)
+		if len(got) != statsHistorySize || got[0] != 5 || got[len(got)-1] != statsHistorySize+4 {
+			t.Errorf("got %d samples from %v to %v, want %d from 5 to %d",
+				len(got), got[0], got[len(got)-1], statsHistorySize, statsHistorySize+4)
+		}
+		if want := time.Duration(statsHistorySize-1) * 2 * time.Second; r.span() != want {
+			t.Errorf("span = %v, want %v", r.span(), want)
+		}
+	})
+
+	t.Run("nil ring", func(t *testing.T) {
+		var r *statsRing
+		if r.series(cpu) != nil || r.span() != 0 {
+			t.Error("a nil ring isn't empty")
+		}
+	})
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		values []float64
+		width  int
+		lo, hi float64
+		want   string
+	}{
+		{nil, 4, 0, 100, "    "},
+		{[]float64{0, 100}, 4, 0, 100, "  ▁█"},
+		{[]float64{0, 50, 100}, 3, 0, 100, "▁▄█"},
+		{[]float64{10, 20, 30, 40, 50}, 3, 0, 50, "▅▆█"},
+		{[]float64{-10, 200}, 2, 0, 100, "▁█"},
+		{[]float64{7, 7, 7}, 3, 7, 7, "▁▁▁"},
+	}
+	for _, tt := range tests {
+		if got := sparkline(tt.values, tt.width, tt.lo, tt.hi); got != tt.want {
+			t.Errorf("sparkline(%v, %d, %v, %v) = %q, want %q", tt.values, tt.width, tt.lo, tt.hi, got, tt.want)
+		}
+	}
+}
+
+func TestWithRates(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	sample := func(at time.Time, rx, tx, read, write float64, ifaces map[string]NetIO) Stats {
+		return Stats{Read: at, NetRx: rx, NetTx: tx, BlkRead: read, BlkWrite: write, Interfaces: ifaces}
+	}
+
+	tests := []struct {
+		name       string
+		prev, cur  Stats
+		want       [4]float64 // rx, tx, read and write rates
+		wantIfaces map[string]NetIO
+	}{
+		{
+			name: "first sample has no rates",
+			cur:  sample(t0, 100, 100, 100, 100, nil),
+		},
+		{
+			name: "bytes per second over the interval",
+			prev: sample(t0, 1000, 2000, 0, 500, nil),
+			cur:  sample(t0.Add(2*time.Second), 3000, 2000, 4096, 1500, nil),
+			want: [4]float64{1000, 0, 2048, 500},
+		},
+		{
+			name: "counter reset after a restart gives zero",
+			prev: sample(t0, 5000, 5000, 5000, 5000, nil),
+			cur:  sample(t0.Add(time.Second), 100, 6000, 10, 5000, nil),
+			want: [4]float64{0, 1000, 0, 0},
+		},
+		{
+			name: "sample not newer than the last one",
+			prev: sample(t0, 0, 0, 0, 0, nil),
+			cur:  sample(t0, 100, 100, 100, 100, nil),
+		},
+		{
+			name: "per interface, new interfaces start without a rate",
+			prev: sample(t0, 0, 0, 0, 0, map[string]NetIO{"eth0": {Rx: 100, Tx: 100}}),
+			cur: sample(t0.Add(time.Second), 0, 0, 0, 0, map[string]NetIO{
+				"eth0": {Rx: 600, Tx: 150},
+				"eth1": {Rx: 50, Tx: 50},
+			}),
+			wantIfaces: map[string]NetIO{
+				"eth0": {Rx: 600, Tx: 150, RxRate: 500, TxRate: 50},
+				"eth1": {Rx: 50, Tx: 50},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.cur.withRates(tt.prev)
+			rates := [4]float64{got.NetRxRate, got.NetTxRate, got.BlkReadRate, got.BlkWriteRate}
+			if rates != tt.want {
+				t.Errorf("rates = %v, want %v", rates, tt.want)
+			}
+			if !reflect.DeepEqual(got.Interfaces, tt.wantIfaces) {
+				t.Errorf("interfaces = %v, want %v", got.Interfaces, tt.wantIfaces)
+			}
+		})
+	}
+}
+
+func TestSyncStatsPrunesHistory(t *testing.T) {
+	m := model{
+		showStats: true,
+		allContainers: []Container{
+			{ID: "a1", Names: "web", Host: "prod", State: "running"},
+			{ID: "b2", Names: "db", Host: "prod", State: "exited"},
+		},
+		statsHistory: map[string]*statsRing{
+			"prod/a1": {}, "prod/b2": {}, "prod/c3": {}, "a1": {},
+		},
+	}
+	m.syncStats()
+	defer m.statsCollector.Stop()
+
+	var keys []string
+	for key := range m.statsHistory {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if want := []string{"prod/a1", "prod/b2"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("history kept for %v, want %v", keys, want)
+	}
+}
@@ -7,12 +7,6 @@ var (
 	highlight = lipgloss.AdaptiveColor{Light: "#874BFD", Dark: "#7D56F4"}
 	special   = lipgloss.AdaptiveColor{Light: "#43BF6D", Dark: "#73F59F"}
 
-	ListHeaderStyle = lipgloss.NewStyle().
-			BorderStyle(lipgloss.NormalBorder()).
-			BorderBottom(true).
-			BorderForeground(subtle).
-			MarginRight(2)
-
 	ListItemStyle = lipgloss.NewStyle().PaddingLeft(1)
 
 	checkMark = lipgloss.NewStyle().SetString("✓").
@@ -28,45 +22,128 @@ var (
 	}
 
 	docStyle = lipgloss.NewStyle().Margin(1, 2)
+)
 
-	tableStyle = lipgloss.NewStyle().
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("240"))
-
-	headerStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("205")).
-			Bold(true).
-			Align(lipgloss.Center)
+// The styles below are built from the active theme by applyTheme.
+var (
+	activeTheme Theme
+
+	ListHeaderStyle lipgloss.Style
+	tableStyle      lipgloss.Style
+	headerStyle     lipgloss.Style
+	selectedStyle   lipgloss.Style
+
+	statusUpStyle     lipgloss.Style
+	statusExitedStyle lipgloss.Style
+	statusPausedStyle lipgloss.Style
+	helpStyle         lipgloss.Style
+
+	// Screen titles, status text and popups
+	titleStyle lipgloss.Style
+	infoStyle  lipgloss.Style
+	mutedStyle lipgloss.Style
+	warnStyle  lipgloss.Style
+	alertStyle lipgloss.Style
+	textStyle  lipgloss.Style
 
-	selectedStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("229")).
-			Background(lipgloss.Color("57")).
-			Bold(true)
+	// Port Colors
+	portHostStyle      lipgloss.Style
+	portContainerStyle lipgloss.Style
 
-	statusUpStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("42")) // Green
+	// Table Enhancements
+	idStyle    lipgloss.Style
+	imageStyle lipgloss.Style
 
-	statusExitedStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("196")) // Red
+	// rowEvenBg is the zebra stripe behind every other row
+	rowEvenBg lipgloss.Color
 
-	helpStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("241")).
-			MarginTop(1)
+	// Log viewer stream markers
+	logStdoutGutterStyle lipgloss.Style
+	logStderrGutterStyle lipgloss.Style
+	logStderrStyle       lipgloss.Style
 
-	// Port Colors
-	portHostStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("45")) // Cyan for host
+	// Volumes no container references
+	volumeOrphanStyle lipgloss.Style
 
-	portContainerStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("214")) // Orange for container
+	// Filter matches in the container table
+	filterMatchStyle lipgloss.Style
 
-	// Table Enhancements
-	idStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("141")) // Purple (Dracula-ish)
+	// Compose project headers in the container table
+	projectHeaderStyle  lipgloss.Style
+	projectPartialStyle lipgloss.Style
 
-	imageStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("243")) // Dimmed gray
+	// Inspect pane
+	inspectSectionStyle lipgloss.Style
+	inspectKeyStyle     lipgloss.Style
+	inspectDimStyle     lipgloss.Style
 
-	rowEvenStyle = lipgloss.NewStyle().
-			Background(lipgloss.Color("235")) // Very dark gray for zebra stripe
+	// Service name prefixes in merged project logs
+	logPrefixColors []lipgloss.Color
 )
+
+func init() {
+	applyTheme(presetThemes[0])
+}
+
+// applyTheme rebuilds every style from t. Views render from these styles
+// on each frame, so a switch shows up on the next redraw.
+func applyTheme(t Theme) {
+	activeTheme = t
+	fg := func(c string) lipgloss.Style {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(c))
+	}
+
+	ListHeaderStyle = lipgloss.NewStyle().
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderBottom(true).
+		BorderForeground(lipgloss.Color(t.Border)).
+		MarginRight(2)
+
+	tableStyle = lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(t.Border))
+
+	headerStyle = fg(t.Accent).Bold(true).Align(lipgloss.Center)
+
+	selectedStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(t.SelectedFg)).
+		Background(lipgloss.Color(t.SelectedBg)).
+		Bold(true)
+
+	statusUpStyle = fg(t.Good)
+	statusExitedStyle = fg(t.Bad)
+	statusPausedStyle = fg(t.Warn)
+	helpStyle = fg(t.Muted).MarginTop(1)
+
+	titleStyle = fg(t.Accent).Bold(true)
+	infoStyle = fg(t.Info)
+	mutedStyle = fg(t.Muted)
+	warnStyle = fg(t.Warn)
+	alertStyle = fg(t.Bad)
+	textStyle = fg(t.Text)
+
+	portHostStyle = fg(t.PortHost)
+	portContainerStyle = fg(t.PortContainer)
+	idStyle = fg(t.ID)
+	imageStyle = fg(t.Dim)
+	rowEvenBg = lipgloss.Color(t.Zebra)
+
+	logStdoutGutterStyle = fg(t.Border)
+	logStderrGutterStyle = fg(t.Bad)
+	logStderrStyle = fg(t.Stderr)
+
+	filterMatchStyle = fg(t.Accent).Bold(true).Underline(true)
+
+	volumeOrphanStyle = fg(t.Warn)
+	projectHeaderStyle = fg(t.Accent).Bold(true)
+	projectPartialStyle = fg(t.Warn)
+
+	inspectSectionStyle = fg(t.Accent).Bold(true)
+	inspectKeyStyle = fg(t.Info)
+	inspectDimStyle = fg(t.Dim)
+
+	logPrefixColors = logPrefixColors[:0]
+	for _, c := range t.Prefixes {
+		logPrefixColors = append(logPrefixColors, lipgloss.Color(c))
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Theme is a colour palette. Every colour on screen comes from the active
+// theme; applyTheme turns it into the package's styles. Colours are ANSI
+// 256 numbers ("205") or hex ("#ff5fd7"); an empty colour means the
+// terminal default.
+type Theme struct {
+	Name string `json:"-"`
+
+	Accent        string   `json:"accent"`         // titles, headings, logo
+	Info          string   `json:"info"`           // status line, prompts, field names
+	Muted         string   `json:"muted"`          // footer help and counters
+	Dim           string   `json:"dim"`            // secondary text such as images
+	Border        string   `json:"border"`         // table borders, stdout gutter
+	Text          string   `json:"text"`           // plain text in popups
+	Zebra         string   `json:"zebra"`          // background of every other row
+	SelectedFg    string   `json:"selected_fg"`    // cursor row
+	SelectedBg    string   `json:"selected_bg"`    // cursor row
+	Good          string   `json:"good"`           // running, healthy, low usage
+	Caution       string   `json:"caution"`        // usage bar above 60%
+	Warn          string   `json:"warn"`           // paused, partial, rows with a warn alert
+	Bad           string   `json:"bad"`            // exited, errors, rows with a bad alert
+	Stderr        string   `json:"stderr"`         // stderr text in the log viewer
+	ID            string   `json:"id"`             // container IDs
+	PortHost      string   `json:"port_host"`      // host side of a port mapping
+	PortContainer string   `json:"port_container"` // container side of a port mapping
+	Beams         []string `json:"beams"`          // the seven rays of the header logo
+	Prefixes      []string `json:"prefixes"`       // service names in merged logs
+}
+
+// presetThemes ship with Prism, in the order the theme key cycles them.
+var presetThemes = []Theme{
+	{
+		Name:   "dark",
+		Accent: "205", Info: "63", Muted: "241", Dim: "243", Border: "240", Text: "255",
+		Zebra: "235", SelectedFg: "229", SelectedBg: "57",
+		Good: "42", Caution: "226", Warn: "214", Bad: "196", Stderr: "210",
+		ID: "141", PortHost: "45", PortContainer: "214",
+		Beams:    []string{"196", "208", "226", "46", "21", "51", "129"},
+		Prefixes: []string{"39", "170", "214", "82", "141", "208", "51", "204"},
+	},
+	{
+		Name:   "light",
+		Accent: "162", Info: "25", Muted: "244", Dim: "242", Border: "248", Text: "16",
+		Zebra: "254", SelectedFg: "16", SelectedBg: "153",
+		Good: "28", Caution: "136", Warn: "166", Bad: "160", Stderr: "124",
+		ID: "91", PortHost: "31", PortContainer: "166",
+		Beams:    []string{"160", "166", "136", "28", "19", "30", "91"},
+		Prefixes: []string{"25", "127", "130", "28", "91", "166", "30", "161"},
+	},
+	{
+		Name:   "high-contrast",
+		Accent: "15", Info: "14", Muted: "252", Dim: "250", Border: "15", Text: "15",
+		Zebra: "", SelectedFg: "16", SelectedBg: "226",
+		Good: "46", Caution: "226", Warn: "214", Bad: "196", Stderr: "203",
+		ID: "13", PortHost: "14", PortContainer: "226",
+		Beams:    []string{"196", "214", "226", "46", "33", "51", "201"},
+		Prefixes: []string{"14", "13", "226", "46", "214", "51", "201", "15"},
+	},
+	{
+		// Okabe-Ito colours: status never relies on telling red from green
+		Name:   "colorblind",
+		Accent: "#CC79A7", Info: "#56B4E9", Muted: "244", Dim: "246", Border: "240", Text: "255",
+		Zebra: "235", SelectedFg: "#000000", SelectedBg: "#56B4E9",
+		Good: "#0072B2", Caution: "#F0E442", Warn: "#E69F00", Bad: "#D55E00", Stderr: "#E69F00",
+		ID: "#CC79A7", PortHost: "#56B4E9", PortContainer: "#E69F00",
+		Beams:    []string{"#D55E00", "#E69F00", "#F0E442", "#009E73", "#0072B2", "#56B4E9", "#CC79A7"},
+		Prefixes: []string{"#56B4E9", "#E69F00", "#009E73", "#F0E442", "#CC79A7", "#D55E00", "#0072B2", "250"},
+	},
+}
+
+// loadThemes returns the presets followed by the user's themes from the
+// config file. A user theme starts as a copy of its "base" preset (dark if
+// not given) and overrides only the colours it sets.
+func loadThemes(user map[string]json.RawMessage) ([]Theme, error) {
+	themes := append([]Theme(nil), presetThemes...)
+	var errs []error
+	for _, name := range sortedKeys(user) {
+		if findTheme(themes, name) >= 0 {
+			errs = append(errs, fmt.Errorf("themes.%s: name already taken by a built-in theme", name))
+			continue
+		}
+		var head struct {
+			Base string `json:"base"`
+		}
+		if err := json.Unmarshal(user[name], &head); err != nil {
+			errs = append(errs, fmt.Errorf("themes.%s: %w", name, err))
+			continue
+		}
+		if head.Base == "" {
+			head.Base = "dark"
+		}
+		base := findTheme(presetThemes, head.Base)
+		if base < 0 {
+			errs = append(errs, fmt.Errorf("themes.%s: unknown base theme %q", name, head.Base))
+			continue
+		}
+		t := presetThemes[base]
+		t.Beams = append([]string(nil), t.Beams...)
+		t.Prefixes = append([]string(nil), t.Prefixes...)
+		overlay := struct {
+			Base string `json:"base"`
+			*Theme
+		}{Theme: &t}
+		dec := json.NewDecoder(bytes.NewReader(user[name]))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&overlay); err != nil {
+			errs = append(errs, fmt.Errorf("themes.%s: %w", name, err))
+			continue
+		}
+		t.Name = name
+		if err := t.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("themes.%s: %w", name, err))
+			continue
+		}
+		themes = append(themes, t)
+	}
+	return themes, errors.Join(errs...)
+}
+
+// findTheme returns the index of the named theme, or -1.
+func findTheme(themes []Theme, name string) int {
+	for i, t := range themes {
+		if t.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// validate checks that every colour is one lipgloss understands.
+func (t Theme) validate() error {
+	check := func(field, c string) error {
+		if c == "" || hexColor.MatchString(c) {
+			return nil
+		}
+		if n, err := strconv.Atoi(c); err == nil && n >= 0 && n <= 255 {
+			return nil
+		}
+		return fmt.Errorf("%s: %q is not an ANSI colour number (0-255) or #rrggbb", field, c)
+	}
+	var errs []error
+	for _, f := range []struct{ field, c string }{
+		{"accent", t.Accent}, {"info", t.Info}, {"muted", t.Muted}, {"dim", t.Dim},
+		{"border", t.Border}, {"text", t.Text}, {"zebra", t.Zebra},
+		{"selected_fg", t.SelectedFg}, {"selected_bg", t.SelectedBg},
+		{"good", t.Good}, {"caution", t.Caution}, {"warn", t.Warn}, {"bad", t.Bad},
+		{"stderr", t.Stderr}, {"id", t.ID}, {"port_host", t.PortHost}, {"port_container", t.PortContainer},
+	} {
+		errs = append(errs, check(f.field, f.c))
+	}
+	if len(t.Beams) != 7 {
+		errs = append(errs, fmt.Errorf("beams: want 7 colours, got %d", len(t.Beams)))
+	}
+	for i, c := range t.Beams {
+		errs = append(errs, check(fmt.Sprintf("beams[%d]", i), c))
+	}
+	if len(t.Prefixes) == 0 {
+		errs = append(errs, errors.New("prefixes: want at least one colour"))
+	}
+	for i, c := range t.Prefixes {
+		errs = append(errs, check(fmt.Sprintf("prefixes[%d]", i), c))
+	}
+	return errors.Join(errs...)
+}
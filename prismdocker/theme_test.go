@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPresetThemesAreValid(t *testing.T) {
+	for _, th := range presetThemes {
+		if err := th.validate(); err != nil {
+			t.Errorf("preset %s: %v", th.Name, err)
+		}
+	}
+}
+
+func TestLoadThemes(t *testing.T) {
+	light := presetThemes[findTheme(presetThemes, "light")]
+	tests := []struct {
+		name     string
+		user     map[string]string
+		check    func(t *testing.T, got Theme) // of the one user theme, if any
+		wantErrs []string
+	}{
+		{
+			name: "presets only",
+		},
+		{
+			name: "overrides on top of dark",
+			user: map[string]string{"mine": `{"accent": "#ff00aa", "zebra": ""}`},
+			check: func(t *testing.T, got Theme) {
+				want := presetThemes[0]
+				want.Name, want.Accent, want.Zebra = "mine", "#ff00aa", ""
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("got %+v, want %+v", got, want)
+				}
+			},
+		},
+		{
+			name: "another base",
+			user: map[string]string{"paper": `{"base": "light", "beams": ["1", "2", "3", "4", "5", "6", "7"]}`},
+			check: func(t *testing.T, got Theme) {
+				if got.Good != light.Good || got.Beams[0] != "1" {
+					t.Errorf("got good %q and beams %v, want light's good %q and the new beams", got.Good, got.Beams, light.Good)
+				}
+			},
+		},
+		{
+			name:     "name of a preset",
+			user:     map[string]string{"dark": `{}`},
+			wantErrs: []string{"themes.dark: name already taken"},
+		},
+		{
+			name:     "unknown base",
+			user:     map[string]string{"x": `{"base": "neon"}`},
+			wantErrs: []string{`themes.x: unknown base theme "neon"`},
+		},
+		{
+			name:     "unknown field",
+			user:     map[string]string{"x": `{"accnet": "1"}`},
+			wantErrs: []string{`themes.x: json: unknown field "accnet"`},
+		},
+		{
+			name:     "not an object",
+			user:     map[string]string{"x": `"dark"`},
+			wantErrs: []string{"themes.x: json: cannot unmarshal"},
+		},
+		{
+			name: "bad colours",
+			user: map[string]string{"x": `{"good": "green", "bad": "256", "id": "#12345", "beams": ["1"], "prefixes": []}`},
+			wantErrs: []string{
+				`themes.x: good: "green" is not an ANSI colour number`,
+				`bad: "256"`,
+				`id: "#12345"`,
+				"beams: want 7 colours, got 1",
+				"prefixes: want at least one colour",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := make(map[string]json.RawMessage, len(tt.user))
+			for name, raw := range tt.user {
+				user[name] = json.RawMessage(raw)
+			}
+			themes, err := loadThemes(user)
+			checkErrs(t, err, tt.wantErrs)
+			if !reflect.DeepEqual(themes[:len(presetThemes)], presetThemes) {
+				t.Fatal("presets changed or not first")
+			}
+			extra := themes[len(presetThemes):]
+			if tt.check == nil {
+				if len(extra) != 0 {
+					t.Fatalf("got user themes %v, want none", extra)
+				}
+				return
+			}
+			if len(extra) != 1 {
+				t.Fatalf("got %d user themes, want 1", len(extra))
+			}
+			tt.check(t, extra[0])
+		})
+	}
+}
+
+func TestLoadThemesDoesNotShareSlices(t *testing.T) {
+	themes, err := loadThemes(map[string]json.RawMessage{"mine": json.RawMessage(`{}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mine := themes[findTheme(themes, "mine")]
+	mine.Beams[0] = "changed"
+	if presetThemes[0].Beams[0] == "changed" {
+		t.Error("a user theme shares its beams with its base preset")
+	}
+}
@@ -0,0 +1,425 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/moby/moby/client"
+)
+
+// Process is one row of `docker top`. PID is the process ID on the host.
+type Process struct {
+	PID     int
+	User    string
+	CPU     float64 // percent of one core
+	Mem     float64 // percent of host memory
+	RSS     float64 // bytes
+	Elapsed int64   // seconds since it started; -1 when not reported
+	Command string
+}
+
+// topArgs are the ps options the daemon runs `docker top` with.
+var topArgs = []string{"-eo", "pid,user,%cpu,%mem,rss,etimes,args"}
+
+// ListProcesses returns the processes running in a container. Columns the
+// daemon doesn't report (Windows containers ignore the ps options) are
+// left empty.
+func ListProcesses(cli *client.Client, containerID string) ([]Process, error) {
+	res, err := cli.ContainerTop(context.Background(), containerID, client.ContainerTopOptions{Arguments: topArgs})
+	if err != nil {
+		return nil, err
+	}
+	col := make(map[string]int, len(res.Titles))
+	for i, t := range res.Titles {
+		col[strings.ToUpper(t)] = i
+	}
+	field := func(row []string, names ...string) string {
+		for _, n := range names {
+			if i, ok := col[n]; ok && i < len(row) {
+				return row[i]
+			}
+		}
+		return ""
+	}
+
+	procs := make([]Process, 0, len(res.Processes))
+	for _, row := range res.Processes {
+		pid, err := strconv.Atoi(field(row, "PID"))
+		if err != nil {
+			continue
+		}
+		cpu, _ := strconv.ParseFloat(field(row, "%CPU"), 64)
+		mem, _ := strconv.ParseFloat(field(row, "%MEM"), 64)
+		rss, _ := strconv.ParseFloat(field(row, "RSS"), 64) // KiB
+		elapsed, err := strconv.ParseInt(field(row, "ELAPSED"), 10, 64)
+		if err != nil {
+			elapsed = -1
+		}
+		procs = append(procs, Process{
+			PID:     pid,
+			User:    field(row, "USER", "UID"),
+			CPU:     cpu,
+			Mem:     mem,
+			RSS:     rss * 1024,
+			Elapsed: elapsed,
+			Command: field(row, "COMMAND", "CMD", "ARGS"),
+		})
+	}
+	return procs, nil
+}
+
+// procListScript prints the uptime, then two lines for every process in
+// the container's own PID namespace: its /proc stat line and its command
+// line.
+const procListScript = `cut -d' ' -f1 /proc/uptime; for d in /proc/[0-9]*; do s=$(cat "$d/stat" 2>/dev/null) || continue; echo "$s"; tr '\0' ' ' < "$d/cmdline" 2>/dev/null; echo; done`
+
+// userHZ is the unit of the start times in /proc/<pid>/stat.
+const userHZ = 100
+
+// insideProcess is a process as seen from inside the container.
+type insideProcess struct {
+	pid     int
+	elapsed float64 // seconds since it started
+	command string
+}
+
+// parseInsideProcesses reads the output of procListScript.
+func parseInsideProcesses(out string) ([]insideProcess, error) {
+	lines := strings.Split(out, "\n")
+	uptime, err := strconv.ParseFloat(strings.TrimSpace(lines[0]), 64)
+	if err != nil {
+		return nil, fmt.Errorf("reading the uptime: %w", err)
+	}
+	var procs []insideProcess
+	for i := 1; i+1 < len(lines); i += 2 {
+		stat := lines[i]
+		pidStr, _, _ := strings.Cut(stat, " ")
+		pid, err := strconv.Atoi(pidStr)
+		if err != nil {
+			continue
+		}
+		// The command name in parentheses may hold spaces; the fields
+		// after it start at field 3, so the start time (22) is the 20th
+		paren := strings.LastIndexByte(stat, ')')
+		if paren < 0 {
+			continue
+		}
+		fields := strings.Fields(stat[paren+1:])
+		if len(fields) < 20 {
+			continue
+		}
+		ticks, err := strconv.ParseInt(fields[19], 10, 64)
+		if err != nil {
+			continue
+		}
+		procs = append(procs, insideProcess{
+			pid:     pid,
+			elapsed: uptime - float64(ticks)/userHZ,
+			command: strings.TrimSpace(lines[i+1]),
+		})
+	}
+	return procs, nil
+}
+
+// matchProcess finds target among the processes inside the container. The
+// two sides are matched on the command line and on how long ago the
+// process started; inside is read slack seconds after procs at most. When
+// more than one process fits, it refuses rather than guess.
+func matchProcess(procs []Process, target Process, inside []insideProcess, slack float64) (int, error) {
+	var current Process
+	found := false
+	for _, p := range procs {
+		if p.PID == target.PID && p.Command == target.Command {
+			current, found = p, true
+		}
+	}
+	if !found {
+		return 0, fmt.Errorf("process %d has exited", target.PID)
+	}
+	if current.Elapsed < 0 {
+		return 0, errors.New("the daemon doesn't report when processes started")
+	}
+	// Host start times are whole seconds, rounded down
+	twins := 0
+	for _, p := range procs {
+		if p.PID != current.PID && p.Command == current.Command && p.Elapsed >= current.Elapsed-1 && p.Elapsed <= current.Elapsed+1 {
+			twins++
+		}
+	}
+	low, high := float64(current.Elapsed)-1, float64(current.Elapsed)+1+slack
+	var matches []int
+	for _, p := range inside {
+		if p.command == current.Command && p.elapsed >= low && p.elapsed <= high {
+			matches = append(matches, p.pid)
+		}
+	}
+	switch {
+	case twins > 0 || len(matches) > 1:
+		return 0, fmt.Errorf("can't tell process %d apart from others with the same command and start time", target.PID)
+	case len(matches) == 0:
+		return 0, fmt.Errorf("process %d not found inside the container", target.PID)
+	}
+	return matches[0], nil
+}
+
+// containerPID maps a process from `docker top`, which lists host PIDs, to
+// its PID inside the container, which is what kill run there needs. It
+// lists both sides afresh and matches them with matchProcess.
+func containerPID(cli *client.Client, containerID string, target Process) (int, error) {
+	start := time.Now()
+	procs, err := ListProcesses(cli, containerID)
+	if err != nil {
+		return 0, err
+	}
+	out, err := ExecOutput(cli, containerID, []string{"sh", "-c", procListScript})
+	if err != nil {
+		return 0, fmt.Errorf("listing processes in the container: %w", err)
+	}
+	inside, err := parseInsideProcesses(out)
+	if err != nil {
+		return 0, fmt.Errorf("listing processes in the container: %w", err)
+	}
+	return matchProcess(procs, target, inside, time.Since(start).Seconds())
+}
+
+// SignalProcess sends sig (a name such as TERM) to a process by running
+// kill inside the container, so it needs a shell there.
+func SignalProcess(cli *client.Client, containerID string, target Process, sig string) error {
+	pid, err := containerPID(cli, containerID, target)
+	if err != nil {
+		return err
+	}
+	_, err = ExecOutput(cli, containerID, []string{"sh", "-c", `kill -s "$0" "$1"`, sig, strconv.Itoa(pid)})
+	return err
+}
+
+// signalOptions are the signals offered by the signal picker.
+var signalOptions = []pickerOption{
+	{"TERM", "ask it to exit"},
+	{"INT", "interrupt, like Ctrl+C"},
+	{"HUP", "hang up; many daemons reload"},
+	{"QUIT", "exit and dump core"},
+	{"KILL", "kill at once; can't be caught"},
+	{"USR1", "user-defined 1"},
+	{"USR2", "user-defined 2"},
+	{"STOP", "freeze"},
+	{"CONT", "resume after STOP"},
+}
+
+type ProcessSortOrder int
+
+const (
+	ProcSortByCPU ProcessSortOrder = iota
+	ProcSortByMem
+	ProcSortByPID
+	ProcSortByCommand
+)
+
+func (s ProcessSortOrder) String() string {
+	switch s {
+	case ProcSortByCPU:
+		return "CPU%"
+	case ProcSortByMem:
+		return "Mem"
+	case ProcSortByPID:
+		return "PID"
+	case ProcSortByCommand:
+		return "Command"
+	default:
+		return "Unknown"
+	}
+}
+
+func sortProcesses(procs []Process, order ProcessSortOrder) {
+	sort.SliceStable(procs, func(i, j int) bool {
+		a, b := procs[i], procs[j]
+		switch order {
+		case ProcSortByCPU:
+			if a.CPU != b.CPU {
+				return a.CPU > b.CPU // descending
+			}
+		case ProcSortByMem:
+			if a.RSS != b.RSS {
+				return a.RSS > b.RSS // descending
+			}
+		case ProcSortByCommand:
+			if a.Command != b.Command {
+				return a.Command < b.Command
+			}
+		}
+		return a.PID < b.PID
+	})
+}
+
+// processesMsg carries a fresh process list for the top screen.
+type processesMsg struct {
+	id    string
+	procs []Process
+	err   error
+}
+
+// processActionMsg reports the result of sending a signal for the footer.
+type processActionMsg struct {
+	done string
+	err  error
+}
+
+func fetchProcesses(cli *client.Client, containerID string) tea.Cmd {
+	return func() tea.Msg {
+		procs, err := ListProcesses(cli, containerID)
+		return processesMsg{containerID, procs, err}
+	}
+}
+
+func sendSignal(cli *client.Client, containerID string, target Process, sig string) tea.Cmd {
+	return func() tea.Msg {
+		err := SignalProcess(cli, containerID, target, sig)
+		return processActionMsg{fmt.Sprintf("Sent SIG%s to %d.", sig, target.PID), err}
+	}
+}
+
+// ── Top screen ─────────────────────────────────────────────────────────
+
+// openTop shows the process list of a container.
+func (m *model) openTop(c Container) tea.Cmd {
+	m.activeView = viewTop
+	m.topID = c.ID
+	m.topHost = c.Host
+	m.topName = c.Names
+	m.processes = nil
+	m.processesErr = nil
+	m.processList = listState{}
+	return fetchProcesses(m.clientFor(c), c.ID)
+}
+
+// setProcesses replaces the list, keeping the cursor on the same process.
+func (m *model) setProcesses(procs []Process) {
+	cur, hadCur := m.currentProcess()
+	sortProcesses(procs, m.processSort)
+	m.processes = procs
+	if hadCur {
+		for i, p := range procs {
+			if p.PID == cur.PID {
+				m.processList.cursor = i
+				break
+			}
+		}
+	}
+	m.moveProcessCursor(0)
+}
+
+// topBodyHeight is the number of table rows that fit on screen.
+func (m model) topBodyHeight() int {
+	// title + table header with its border, footer with its top margin
+	h := m.height - 1 - 3 - 2
+	if h < 1 {
+		h = 1
+	}
+	return h
+}
+
+func (m *model) moveProcessCursor(delta int) {
+	m.processList.move(delta, len(m.processes), m.topBodyHeight())
+}
+
+func (m model) currentProcess() (Process, bool) {
+	if m.processList.cursor < len(m.processes) {
+		return m.processes[m.processList.cursor], true
+	}
+	return Process{}, false
+}
+
+// renderTopView renders the full-screen process list.
+func (m model) renderTopView() string {
+	var cpu float64
+	for _, p := range m.processes {
+		cpu += p.CPU
+	}
+	title := titleStyle.Render(fmt.Sprintf("Processes: %s (%d)", m.topName, len(m.processes)))
+	title += "  " + infoStyle.Render(fmt.Sprintf("CPU %.1f%% | Sort: %s", cpu, m.processSort))
+	if m.processesErr != nil {
+		title += "  " + statusExitedStyle.Render("✖ "+m.processesErr.Error())
+	}
+
+	available := m.width - 10
+	if available < 40 {
+		available = 40
+	}
+	wPID, wUser, wCPU, wMem, wRSS := 9, 12, 8, 8, 9
+	wCmd := available - wPID - wUser - wCPU - wMem - wRSS
+	if wCmd < 20 {
+		wCmd = 20
+	}
+
+	right := ListItemStyle.Copy().Align(lipgloss.Right)
+	tHeader := tableStyle.Render(lipgloss.JoinHorizontal(lipgloss.Top,
+		ListItemStyle.Width(2).Render(""),
+		right.Width(wPID).Render("PID"),
+		ListItemStyle.Width(wUser).Render("User"),
+		right.Width(wCPU).Render("CPU%"),
+		right.Width(wMem).Render("MEM%"),
+		right.Width(wRSS).Render("RSS"),
+		ListItemStyle.Width(wCmd).Render("Command"),
+	))
+
+	bodyH := m.topBodyHeight()
+	start, end := m.processList.window(len(m.processes), bodyH)
+
+	var rows []string
+	if len(m.processes) == 0 && m.processesErr == nil {
+		rows = append(rows, "Loading...")
+	}
+	for i := start; i < end; i++ {
+		p := m.processes[i]
+		selected := i == m.processList.cursor
+		style := ListItemStyle
+		if selected {
+			style = selectedStyle
+		} else if i%2 == 0 {
+			style = style.Copy().Background(rowEvenBg) // Zebra stripe
+		}
+		cursor := "  "
+		if selected {
+			cursor = "> "
+		}
+		// Unpadded cells, so the right-aligned numbers line up with the
+		// header whether or not the row is selected
+		style = style.Copy().PaddingLeft(0)
+		num := style.Copy().Align(lipgloss.Right)
+		pid := strconv.Itoa(p.PID)
+		if !selected {
+			pid = idStyle.Render(pid)
+		}
+		cmd := truncate(p.Command, wCmd-2)
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top,
+			style.Render(cursor),
+			num.Width(wPID).Render(pid),
+			style.Width(wUser).Render(" "+truncate(p.User, wUser-2)),
+			num.Width(wCPU).Render(fmt.Sprintf("%.1f", p.CPU)),
+			num.Width(wMem).Render(fmt.Sprintf("%.1f", p.Mem)),
+			num.Width(wRSS).Render(formatBytesShort(p.RSS)),
+			style.Width(wCmd).Render(" "+cmd),
+		))
+	}
+	for len(rows) < bodyH {
+		rows = append(rows, "")
+	}
+
+	footerText := m.keys.footer("top")
+	if m.statusMsg != "" {
+		footerText = m.statusMsg
+	}
+	return lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		tHeader,
+		lipgloss.JoinVertical(lipgloss.Left, rows...),
+		helpStyle.Render(footerText),
+	)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseInsideProcesses(t *testing.T) {
+	// start time is field 22; the command name may hold spaces and parens
+	out := strings.Join([]string{
+		"1000.50",
+		"1 (nginx) S 0 1 1 0 -1 4194560 100 0 0 0 1 1 0 0 20 0 1 0 50 1000 100",
+		"nginx: master process nginx -g daemon off; ",
+		"7 (my (odd) proc) S 1 7 7 0 -1 4194560 100 0 0 0 1 1 0 0 20 0 1 0 90050 1000 100",
+		"worker --id 2 ",
+		"9 (gone) Z",
+		"",
+		"",
+	}, "\n")
+	got, err := parseInsideProcesses(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []insideProcess{
+		{pid: 1, elapsed: 1000, command: "nginx: master process nginx -g daemon off;"},
+		{pid: 7, elapsed: 100, command: "worker --id 2"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	if _, err := parseInsideProcesses("sh: /proc/uptime: not found\n"); err == nil {
+		t.Error("no error without an uptime")
+	}
+}
+
+func TestMatchProcess(t *testing.T) {
+	// Host PIDs are not in the same order as the container's: the older
+	// worker has the higher PID inside
+	procs := []Process{
+		{PID: 4001, Elapsed: 3600, Command: "nginx"},
+		{PID: 4100, Elapsed: 600, Command: "worker"},
+		{PID: 4200, Elapsed: 30, Command: "worker"},
+		{PID: 4300, Elapsed: 12, Command: "job"},
+		{PID: 4301, Elapsed: 12, Command: "job"},
+	}
+	inside := []insideProcess{
+		{pid: 1, elapsed: 3600.4, command: "nginx"},
+		{pid: 8, elapsed: 30.9, command: "worker"},
+		{pid: 9, elapsed: 601.2, command: "worker"},
+		{pid: 12, elapsed: 12.1, command: "job"},
+		{pid: 13, elapsed: 12.3, command: "job"},
+	}
+	tests := []struct {
+		name    string
+		procs   []Process
+		target  Process
+		want    int
+		wantErr string
+	}{
+		{name: "by start time, not PID order", procs: procs, target: procs[1], want: 9},
+		{name: "the younger twin", procs: procs, target: procs[2], want: 8},
+		{name: "same command, same second", procs: procs, target: procs[3], wantErr: "can't tell process 4300 apart"},
+		{
+			name:    "exited since the list was shown",
+			procs:   procs[:2],
+			target:  Process{PID: 4200, Elapsed: 30, Command: "worker"},
+			wantErr: "process 4200 has exited",
+		},
+		{
+			name:    "PID reused by another command",
+			procs:   []Process{{PID: 4100, Elapsed: 1, Command: "sleep 5"}},
+			target:  procs[1],
+			wantErr: "process 4100 has exited",
+		},
+		{
+			name:    "no start times",
+			procs:   []Process{{PID: 4001, Elapsed: -1, Command: "nginx"}},
+			target:  Process{PID: 4001, Elapsed: -1, Command: "nginx"},
+			wantErr: "doesn't report when processes started",
+		},
+		{
+			name:    "not inside",
+			procs:   []Process{{PID: 5000, Elapsed: 100, Command: "nginx"}},
+			target:  Process{PID: 5000, Elapsed: 100, Command: "nginx"},
+			wantErr: "process 5000 not found inside",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := matchProcess(tt.procs, tt.target, inside, 1)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("PID = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
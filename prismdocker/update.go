@@ -1,6 +1,8 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"os/exec"
 	"strings"
 	"time"
@@ -10,14 +12,22 @@ import (
 )
 
 type tickMsg time.Time
-type containersMsg []Container
-type errMsg struct{ err error }
 type actionMsg struct{ err error }
 type logLineMsg string
-type execDoneMsg struct{ err error }
 type openBrowserMsg struct{}
 
-func (e errMsg) Error() string { return e.err.Error() }
+// containersMsg is a full listing from cli. Messages from a client that
+// is no longer current, after a context switch, are dropped.
+type containersMsg struct {
+	cli        *client.Client
+	containers []Container
+}
+
+// connErrMsg reports that cli couldn't reach its daemon.
+type connErrMsg struct {
+	cli *client.Client
+	err error
+}
 
 func waitForTick() tea.Cmd {
 	return tea.Tick(time.Second*2, func(t time.Time) tea.Msg {
@@ -29,9 +39,9 @@ func fetchContainers(cli *client.Client) tea.Cmd {
 	return func() tea.Msg {
 		containers, err := ListContainers(cli)
 		if err != nil {
-			return errMsg{err}
+			return connErrMsg{cli, err}
 		}
-		return containersMsg(containers)
+		return containersMsg{cli, containers}
 	}
 }
 
@@ -45,58 +55,475 @@ func doAction(fn func() error) tea.Cmd {
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// ── Text prompt ────────────────────────────────────────────────
+		if m.prompt != nil {
+			return m, m.updatePrompt(msg)
+		}
+
+		// ── Picker popup ───────────────────────────────────────────────
+		if m.picker != nil {
+			return m, m.updatePicker(msg)
+		}
+
+		// ── Exec dialog ────────────────────────────────────────────────
+		if m.execDialog != nil {
+			return m, m.updateExecDialog(msg)
+		}
+
+		// ── Shell pane ─────────────────────────────────────────────────
+		if m.activeView == viewShell {
+			return m, m.updateShell(msg)
+		}
+
 		// ── Log view mode ──────────────────────────────────────────────
 		if m.activeView == viewLogs {
-			switch msg.String() {
-			case "esc", "q":
+			if m.logFilterMode {
+				switch msg.String() {
+				case "esc", "enter", "/":
+					m.logFilterMode = false
+				case "backspace":
+					if len(m.logFilter) > 0 {
+						m.logFilter = m.logFilter[:len(m.logFilter)-1]
+					}
+				default:
+					if len(msg.String()) == 1 {
+						m.logFilter += msg.String()
+					}
+				}
+				if m.logFollow {
+					m.logOffset = m.maxLogOffset()
+				} else if m.logOffset > m.maxLogOffset() {
+					m.logOffset = m.maxLogOffset()
+				}
+				return m, nil
+			}
+
+			switch m.keys.action("logs", msg.String()) {
+			case "back":
+				m.closeLogs()
 				m.activeView = viewContainers
-				m.logLines = nil
-				m.logFilter = ""
-				m.logFilterMode = false
+			case "filter":
+				m.logFilterMode = true
+			case "streams":
+				m.logSources = (m.logSources + 1) % 3
+				if m.logFollow || m.logOffset > m.maxLogOffset() {
+					m.logOffset = m.maxLogOffset()
+				}
+			case "up":
+				m.scrollLogs(-1)
+			case "down":
+				m.scrollLogs(1)
+			case "page-up":
+				m.scrollLogs(-m.logBodyHeight())
+			case "page-down":
+				m.scrollLogs(m.logBodyHeight())
+			case "top":
+				m.logFollow = false
 				m.logOffset = 0
-			case "/":
-				m.logFilterMode = !m.logFilterMode
-			case "up", "k":
-				if m.logOffset > 0 {
-					m.logOffset--
+			case "follow":
+				m.logFollow = true
+				m.logOffset = m.maxLogOffset()
+			}
+			return m, nil
+		}
+
+		// ── Stats graphs ───────────────────────────────────────────────
+		if m.activeView == viewStats {
+			switch m.keys.action("stats", msg.String()) {
+			case "back":
+				m.activeView = viewContainers
+				return m, m.syncStats()
+			case "prev":
+				m.moveGraphTarget(-1)
+			case "next":
+				m.moveGraphTarget(1)
+			case "logs":
+				if c, ok := m.graphTarget(); ok {
+					return m, m.openLogs(c.Host, c.ID)
+				}
+			}
+			return m, nil
+		}
+
+		// ── Process list ───────────────────────────────────────────────
+		if m.activeView == viewTop {
+			switch m.keys.action("top", msg.String()) {
+			case "back":
+				m.activeView = viewContainers
+				m.processes = nil
+				m.processesErr = nil
+			case "up":
+				m.moveProcessCursor(-1)
+			case "down":
+				m.moveProcessCursor(1)
+			case "page-up":
+				m.moveProcessCursor(-m.topBodyHeight())
+			case "page-down":
+				m.moveProcessCursor(m.topBodyHeight())
+			case "sort":
+				m.processSort = (m.processSort + 1) % 4
+				m.setProcesses(m.processes)
+			case "signal":
+				if p, ok := m.currentProcess(); ok {
+					host, id := m.topHost, m.topID
+					m.openPicker(fmt.Sprintf("Send signal to %d (%s)", p.PID, truncate(p.Command, 30)), signalOptions,
+						func(m *model, sig string) tea.Cmd {
+							m.statusMsg = fmt.Sprintf("Sending SIG%s to %d...", sig, p.PID)
+							return sendSignal(m.clientOn(host), id, p, sig)
+						})
+				}
+			case "refresh":
+				return m, fetchProcesses(m.clientOn(m.topHost), m.topID)
+			}
+			return m, nil
+		}
+
+		// ── Health log ─────────────────────────────────────────────────
+		if m.activeView == viewHealth {
+			switch m.keys.action("health", msg.String()) {
+			case "back":
+				m.activeView = viewContainers
+				m.healthLines = nil
+				m.healthErr = nil
+			case "up":
+				m.scrollHealth(-1)
+			case "down":
+				m.scrollHealth(1)
+			case "page-up":
+				m.scrollHealth(-m.healthBodyHeight())
+			case "page-down":
+				m.scrollHealth(m.healthBodyHeight())
+			case "logs":
+				m.healthLines = nil
+				m.healthErr = nil
+				return m, m.openLogs(m.healthHost, m.healthID)
+			}
+			return m, nil
+		}
+
+		// ── Inspect view mode ──────────────────────────────────────────
+		if m.activeView == viewInspect {
+			switch m.keys.action("inspect", msg.String()) {
+			case "back":
+				m.activeView = viewContainers
+				m.inspectLines = nil
+				m.inspectErr = nil
+			case "up":
+				m.scrollInspect(-1)
+			case "down":
+				m.scrollInspect(1)
+			case "page-up":
+				m.scrollInspect(-m.inspectBodyHeight())
+			case "page-down":
+				m.scrollInspect(m.inspectBodyHeight())
+			case "top":
+				m.inspectOffset = 0
+			case "bottom":
+				m.inspectOffset = m.maxInspectOffset()
+			case "logs":
+				m.inspectLines = nil
+				m.inspectErr = nil
+				return m, m.openLogs(m.inspectHost, m.inspectID)
+			}
+			return m, nil
+		}
+
+		// ── Confirm dialog mode (images, volumes, networks) ────────────
+		if m.confirmMode && m.activeView != viewContainers {
+			switch m.keys.action("confirm", msg.String()) {
+			case "yes":
+				m.confirmMode = false
+				switch m.confirmAction {
+				case "remove-image":
+					ref := m.confirmTarget
+					m.statusMsg = "Removing " + ref + "..."
+					return m, doImageAction(m.dockerClient, "Removed "+ref+".", func(cli *client.Client) error {
+						return RemoveImage(cli, ref)
+					})
+				case "prune-images":
+					m.statusMsg = "Pruning dangling images..."
+					return m, pruneImages(m.dockerClient)
+				case "remove-volume":
+					name := m.confirmTarget
+					m.statusMsg = "Removing " + name + "..."
+					return m, doVolumeAction(m.dockerClient, "Removed "+name+".", func(cli *client.Client) error {
+						return RemoveVolume(cli, name)
+					})
+				case "prune-volumes":
+					m.statusMsg = "Pruning unused volumes..."
+					return m, pruneVolumes(m.dockerClient)
+				case "remove-network":
+					name := m.confirmTarget
+					m.statusMsg = "Removing " + name + "..."
+					return m, doNetworkAction(m.dockerClient, "Removed "+name+".", func(cli *client.Client) error {
+						return RemoveNetwork(cli, name)
+					})
+				}
+			case "no":
+				m.confirmMode = false
+			}
+			return m, nil
+		}
+
+		// ── Images view mode ───────────────────────────────────────────
+		if m.activeView == viewImages {
+			switch m.keys.action("images", msg.String()) {
+			case "back":
+				if m.pull != nil {
+					m.statusMsg = "Cancelled the pull of " + m.pull.ref + "."
+					m.statusTick = 3
+					m.cancelPull()
+					return m, nil
+				}
+				m.activeView = viewContainers
+			case "volumes":
+				m.cancelPull()
+				return m, m.switchView(viewVolumes)
+			case "networks":
+				m.cancelPull()
+				return m, m.switchView(viewNetworks)
+			case "up":
+				m.moveImageCursor(-1)
+			case "down":
+				m.moveImageCursor(1)
+			case "page-up":
+				m.moveImageCursor(-m.imageBodyHeight())
+			case "page-down":
+				m.moveImageCursor(m.imageBodyHeight())
+			case "refresh":
+				return m, fetchImages(m.dockerClient)
+			case "sort":
+				m.imageSort = (m.imageSort + 1) % 4
+				sortImages(m.images, m.imageSort, imageUsage(m.allContainers))
+				m.imageList = listState{}
+			case "remove":
+				if img, ok := m.currentImage(); ok {
+					m.confirmMode = true
+					m.confirmAction = "remove-image"
+					m.confirmTarget = img.Ref()
+					m.confirmText = "Remove image " + img.Ref() + "?"
+				}
+			case "tag":
+				if img, ok := m.currentImage(); ok {
+					source := img.Ref()
+					initial := img.Repository + ":"
+					if img.Dangling {
+						initial = ""
+					}
+					m.openPrompt("Tag "+source+" as", initial, func(m *model, target string) tea.Cmd {
+						if target == "" {
+							return nil
+						}
+						m.statusMsg = "Tagging " + source + "..."
+						return doImageAction(m.dockerClient, "Tagged "+source+" as "+target+".", func(cli *client.Client) error {
+							return TagImage(cli, source, target)
+						})
+					})
+				}
+			case "pull":
+				initial := ""
+				if img, ok := m.currentImage(); ok && !img.Dangling {
+					initial = img.Ref()
+				}
+				m.openPrompt("Pull image", initial, func(m *model, ref string) tea.Cmd {
+					if ref == "" {
+						return nil
+					}
+					if m.pull != nil {
+						m.statusMsg = "A pull is already running"
+						m.statusTick = 3
+						return nil
+					}
+					m.pullSession++
+					m.pull = newImagePull(ref, m.pullSession)
+					return tea.Batch(runPull(m.dockerClient, m.pull), waitForPullProgress(m.pull))
+				})
+			case "prune":
+				m.statusMsg = "Checking dangling images..."
+				return m, previewImagePrune(m.dockerClient)
+			}
+			return m, nil
+		}
+
+		// ── Volumes view mode ──────────────────────────────────────────
+		if m.activeView == viewVolumes {
+			switch m.keys.action("volumes", msg.String()) {
+			case "back":
+				m.activeView = viewContainers
+			case "images":
+				return m, m.switchView(viewImages)
+			case "networks":
+				return m, m.switchView(viewNetworks)
+			case "up":
+				m.moveVolumeCursor(-1)
+			case "down":
+				m.moveVolumeCursor(1)
+			case "page-up":
+				m.moveVolumeCursor(-m.volumeBodyHeight())
+			case "page-down":
+				m.moveVolumeCursor(m.volumeBodyHeight())
+			case "refresh":
+				return m, fetchVolumes(m.dockerClient)
+			case "select-users":
+				if v, ok := m.currentVolume(); ok {
+					m.selectVolumeUsers(v)
+				}
+			case "remove":
+				if v, ok := m.currentVolume(); ok {
+					m.confirmMode = true
+					m.confirmAction = "remove-volume"
+					m.confirmTarget = v.Name
+					m.confirmText = "Remove volume " + v.Name + "?"
+					if users := volumeUsers(m.allContainers)[v.Name]; len(users) > 0 {
+						m.confirmText = fmt.Sprintf("Volume %s is used by %d container(s). Remove anyway?", v.Name, len(users))
+					}
+				}
+			case "prune":
+				orphans, size := orphanVolumes(m.volumes, volumeUsers(m.allContainers))
+				if len(orphans) == 0 {
+					m.statusMsg = "No unused volumes to prune."
+					m.statusTick = 3
+				} else {
+					m.confirmMode = true
+					m.confirmAction = "prune-volumes"
+					m.confirmText = fmt.Sprintf("Prune %d unused volume(s) (%s)? Their data is deleted.", len(orphans), formatBytes(float64(size)))
+				}
+			}
+			return m, nil
+		}
+
+		// ── Networks view mode ─────────────────────────────────────────
+		if m.activeView == viewNetworks {
+			switch m.keys.action("networks", msg.String()) {
+			case "back":
+				m.activeView = viewContainers
+			case "images":
+				return m, m.switchView(viewImages)
+			case "volumes":
+				return m, m.switchView(viewVolumes)
+			case "up":
+				m.moveNetworkCursor(-1)
+			case "down":
+				m.moveNetworkCursor(1)
+			case "page-up":
+				m.moveNetworkCursor(-m.networkBodyHeight())
+			case "page-down":
+				m.moveNetworkCursor(m.networkBodyHeight())
+			case "refresh":
+				return m, tea.Batch(fetchNetworks(m.dockerClient), fetchContainers(m.dockerClient))
+			case "create":
+				m.openPrompt("Create network", "", func(m *model, name string) tea.Cmd {
+					if name == "" {
+						return nil
+					}
+					m.statusMsg = "Creating " + name + "..."
+					return doNetworkAction(m.dockerClient, "Created "+name+".", func(cli *client.Client) error {
+						return CreateNetwork(cli, name)
+					})
+				})
+			case "remove":
+				if n, ok := m.currentNetwork(); ok {
+					if n.builtin() {
+						m.statusMsg = "Can't remove the built-in " + n.Name + " network"
+						m.statusTick = 3
+						break
+					}
+					m.confirmMode = true
+					m.confirmAction = "remove-network"
+					m.confirmTarget = n.Name
+					m.confirmText = "Remove network " + n.Name + "?"
+					if users := networkMembers(m.allContainers)[n.Name]; len(users) > 0 {
+						m.confirmText = fmt.Sprintf("Network %s has %d container(s) attached. Remove anyway?", n.Name, len(users))
+					}
 				}
-			case "down", "j":
-				m.logOffset++
-			case "backspace":
-				if m.logFilterMode && len(m.logFilter) > 0 {
-					m.logFilter = m.logFilter[:len(m.logFilter)-1]
+			case "connect":
+				n, ok := m.currentNetwork()
+				c, hasTarget := m.networkTarget()
+				switch {
+				case !ok:
+				case !hasTarget:
+					m.statusMsg = "No container selected in the container list"
+					m.statusTick = 3
+				case connectedTo(c, n.Name):
+					m.statusMsg = c.Names + " is already on " + n.Name
+					m.statusTick = 3
+				default:
+					m.statusMsg = "Connecting " + c.Names + " to " + n.Name + "..."
+					return m, doNetworkAction(m.dockerClient, "Connected "+c.Names+" to "+n.Name+".", func(cli *client.Client) error {
+						return ConnectNetwork(cli, n.ID, c.ID)
+					})
 				}
-			default:
-				if m.logFilterMode && len(msg.String()) == 1 {
-					m.logFilter += msg.String()
+			case "disconnect":
+				n, ok := m.currentNetwork()
+				c, hasTarget := m.networkTarget()
+				switch {
+				case !ok:
+				case !hasTarget:
+					m.statusMsg = "No container selected in the container list"
+					m.statusTick = 3
+				case !connectedTo(c, n.Name):
+					m.statusMsg = c.Names + " is not on " + n.Name
+					if on := networkNames(c); on != "" {
+						m.statusMsg += " (on " + on + ")"
+					}
+					m.statusTick = 3
+				default:
+					m.statusMsg = "Disconnecting " + c.Names + " from " + n.Name + "..."
+					return m, doNetworkAction(m.dockerClient, "Disconnected "+c.Names+" from "+n.Name+".", func(cli *client.Client) error {
+						return DisconnectNetwork(cli, n.ID, c.ID)
+					})
 				}
 			}
 			return m, nil
 		}
 
+		// ── Batch summary popup ────────────────────────────────────────
+		if m.batchSummary {
+			// Any key dismisses it
+			m.batchSummary = false
+			return m, nil
+		}
+
 		// ── Confirm dialog mode ────────────────────────────────────────
 		if m.confirmMode {
-			switch msg.String() {
-			case "y", "Y":
+			switch m.keys.action("confirm", msg.String()) {
+			case "yes":
 				m.confirmMode = false
-				if m.confirmAction == "remove" && m.cursor < len(m.filteredContainers) {
-					c := m.filteredContainers[m.cursor]
+				if m.confirmAction == "remove-selected" {
+					return m, m.startBatch(batchRemove)
+				}
+				// The row asked about, even if events have moved the
+				// cursor onto another since
+				if c, ok := m.containerByKey(m.confirmTarget); ok && m.confirmAction == "remove" {
+					cli := m.clientFor(c)
 					return m, doAction(func() error {
-						return RemoveContainer(m.dockerClient, c.ID)
+						return RemoveContainer(cli, c.ID)
 					})
 				}
-			case "n", "N", "esc":
+			case "no":
 				m.confirmMode = false
 			}
 			return m, nil
 		}
 
 		// ── Normal container view ──────────────────────────────────────
-		switch msg.String() {
-		case "q", "ctrl+c":
+		if m.filterMode {
+			return m, m.updateFilterBar(msg)
+		}
+		switch m.keys.action("containers", msg.String()) {
+		case "quit":
 			return m, tea.Quit
 
-		case "up", "k":
+		case "images":
+			return m, m.switchView(viewImages)
+
+		case "volumes":
+			return m, m.switchView(viewVolumes)
+
+		case "networks":
+			return m, m.switchView(viewNetworks)
+
+		case "up":
 			if m.cursor > 0 {
 				m.cursor--
 				if m.cursor < m.tableOffset {
@@ -104,117 +531,259 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
-		case "down", "j":
-			if m.cursor < len(m.filteredContainers)-1 {
+		case "down":
+			if m.cursor < len(m.rows)-1 {
 				m.cursor++
-				headerHeight := 10
-				footerHeight := 2
-				tableHeight := m.height - headerHeight - footerHeight
-				if tableHeight < 1 {
-					tableHeight = 1
-				}
-				if m.cursor >= m.tableOffset+tableHeight {
-					m.tableOffset = m.cursor - tableHeight + 1
-				}
+				m.scrollToCursor()
 			}
 
-		case "r":
-			return m, fetchContainers(m.dockerClient)
+		case "refresh":
+			return m, m.refreshContainers()
 
-		case "s":
-			if m.showStats {
-				m.sortOrder = (m.sortOrder + 1) % 6
-			} else {
-				m.sortOrder = (m.sortOrder + 1) % 4
-			}
-			m.filteredContainers = sortAndFilter(m.allContainers, m.sortOrder, m.showAll, m.stats)
+		case "contexts":
+			m.openContexts()
+
+		case "hosts":
+			return m, m.toggleHosts()
+
+		case "sort":
+			m.sortOrder = m.nextSortOrder()
+			m.refilter()
 			m.cursor = 0
 			m.tableOffset = 0
 
-		case "a":
+		case "toggle-all":
 			m.showAll = !m.showAll
-			m.filteredContainers = sortAndFilter(m.allContainers, m.sortOrder, m.showAll, m.stats)
+			m.refilter()
 			m.cursor = 0
 			m.tableOffset = 0
 
-		case "t":
+		case "toggle-group":
+			m.groupByProject = !m.groupByProject
+			m.refilter()
+			m.cursor = 0
+			m.tableOffset = 0
+
+		case "cycle-theme":
+			m.themeIndex = (m.themeIndex + 1) % len(m.themes)
+			applyTheme(m.themes[m.themeIndex])
+			m.statusMsg = "Theme: " + m.themes[m.themeIndex].Name
+			m.statusTick = 3
+
+		case "toggle-fold": // Fold / unfold a project; on a container, open a shell
+			if g, ok := m.currentGroup(); ok {
+				m.setCollapsed(g.Name, !m.collapsed[g.Name])
+				return m, nil
+			}
+			return m, m.execShell()
+
+		case "fold":
+			m.collapseCurrent()
+
+		case "unfold":
+			if g, ok := m.currentGroup(); ok {
+				m.setCollapsed(g.Name, false)
+			}
+
+		case "toggle-stats":
 			m.showStats = !m.showStats
-			if m.showStats {
-				return m, fetchAllStats(m.dockerClient, m.filteredContainers)
-			} else {
-				if m.sortOrder == SortByCPU || m.sortOrder == SortByMem {
-					m.sortOrder = SortByState
-					m.filteredContainers = sortAndFilter(m.allContainers, m.sortOrder, m.showAll, m.stats)
-				}
+			if !m.showStats && m.sortOrder.needsStats() {
+				m.sortOrder = SortByState
+				m.refilter()
+			}
+			return m, m.syncStats()
+
+		// ── Selection ──────────────────────────────────────────────────
+		case "mark": // Toggle mark on the current row; on a header, the whole project
+			if g, ok := m.currentGroup(); ok {
+				m.toggleProjectSelected(g.Name)
+			} else if c, ok := m.currentContainer(); ok {
+				m.toggleSelected(c)
+			}
+			if m.cursor < len(m.rows)-1 {
+				m.cursor++
+				m.scrollToCursor()
 			}
 
+		case "mark-all":
+			m.selectAllVisible()
+
+		case "invert-marks":
+			m.invertSelection()
+
+		case "clear-marks":
+			m.selected = make(map[string]bool)
+
 		// ── Container actions ──────────────────────────────────────────
-		case "S": // Stop
-			if m.cursor < len(m.filteredContainers) {
-				c := m.filteredContainers[m.cursor]
+		case "stop":
+			if len(m.selected) > 0 {
+				return m, m.startBatch(batchStop(m.stopTimeout))
+			}
+			if g, ok := m.currentGroup(); ok {
+				return m, m.runProjectBatch(g, batchStop(m.stopTimeout))
+			}
+			if c, ok := m.currentContainer(); ok {
 				if c.State == "running" {
 					m.statusMsg = "Stopping " + c.Names + "..."
+					cli, timeout := m.clientFor(c), m.stopTimeout
 					return m, doAction(func() error {
-						return StopContainer(m.dockerClient, c.ID)
+						return StopContainer(cli, c.ID, timeout)
 					})
 				}
 			}
 
-		case "u": // Start (Up)
-			if m.cursor < len(m.filteredContainers) {
-				c := m.filteredContainers[m.cursor]
+		case "start":
+			if len(m.selected) > 0 {
+				return m, m.startBatch(batchStart)
+			}
+			if g, ok := m.currentGroup(); ok {
+				return m, m.runProjectBatch(g, batchStart)
+			}
+			if c, ok := m.currentContainer(); ok {
 				if c.State != "running" {
 					m.statusMsg = "Starting " + c.Names + "..."
+					cli := m.clientFor(c)
 					return m, doAction(func() error {
-						return StartContainer(m.dockerClient, c.ID)
+						return StartContainer(cli, c.ID)
 					})
 				}
 			}
 
-		case "R": // Restart
-			if m.cursor < len(m.filteredContainers) {
-				c := m.filteredContainers[m.cursor]
+		case "restart":
+			if len(m.selected) > 0 {
+				return m, m.startBatch(batchRestart(m.stopTimeout))
+			}
+			if g, ok := m.currentGroup(); ok {
+				return m, m.runProjectBatch(g, batchRestart(m.stopTimeout))
+			}
+			if c, ok := m.currentContainer(); ok {
 				m.statusMsg = "Restarting " + c.Names + "..."
+				cli, timeout := m.clientFor(c), m.stopTimeout
 				return m, doAction(func() error {
-					return RestartContainer(m.dockerClient, c.ID)
+					return RestartContainer(cli, c.ID, timeout)
 				})
 			}
 
-		case "x": // Remove (with confirm)
-			if m.cursor < len(m.filteredContainers) {
+		case "remove": // asks for confirmation first
+			if len(m.selected) > 0 {
+				m.confirmMode = true
+				m.confirmAction = "remove-selected"
+				m.confirmText = fmt.Sprintf("Remove %d selected containers?", len(m.selectedContainers()))
+			} else if c, ok := m.currentContainer(); ok {
 				m.confirmMode = true
 				m.confirmAction = "remove"
+				m.confirmTarget = c.key()
+				m.confirmText = "Remove container " + c.Names + "?"
 			}
 
-		case "l": // Logs
-			if m.cursor < len(m.filteredContainers) {
-				c := m.filteredContainers[m.cursor]
-				m.activeView = viewLogs
-				m.logLines = nil
-				m.logFilter = ""
-				m.logFilterMode = false
-				m.logOffset = 0
-				m.logContainer = c.ID
-				return m, fetchLogs(m.dockerClient, c.ID)
+		case "pause":
+			if len(m.selected) > 0 {
+				return m, m.startBatch(batchPause)
+			}
+			if g, ok := m.currentGroup(); ok {
+				return m, m.runProjectBatch(g, batchPause)
+			}
+			if c, ok := m.currentContainer(); ok {
+				switch c.State {
+				case "running":
+					m.statusMsg = "Pausing " + c.Names + "..."
+					cli := m.clientFor(c)
+					return m, doAction(func() error {
+						return PauseContainer(cli, c.ID)
+					})
+				case "paused":
+					m.statusMsg = "Resuming " + c.Names + "..."
+					cli := m.clientFor(c)
+					return m, doAction(func() error {
+						return UnpauseContainer(cli, c.ID)
+					})
+				}
 			}
 
-		case "enter", "i": // Shell exec
-			if m.cursor < len(m.filteredContainers) {
-				c := m.filteredContainers[m.cursor]
-				if c.State == "running" {
-					cmd := exec.Command("docker", "exec", "-it", c.ID, "/bin/sh")
-					return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
-						return execDoneMsg{err}
+		case "kill": // picks the signal first
+			var title string
+			var run func(m *model, sig string) tea.Cmd
+			if len(m.selected) > 0 {
+				title = fmt.Sprintf("Send a signal to %d marked containers", len(m.selectedContainers()))
+				run = func(m *model, sig string) tea.Cmd { return m.startBatch(batchKill(sig)) }
+			} else if g, ok := m.currentGroup(); ok {
+				title = "Send a signal to project " + g.Name
+				run = func(m *model, sig string) tea.Cmd { return m.runProjectBatch(g, batchKill(sig)) }
+			} else if c, ok := m.currentContainer(); ok && (c.State == "running" || c.State == "paused") {
+				title = "Send a signal to " + c.Names
+				run = func(m *model, sig string) tea.Cmd {
+					m.statusMsg = fmt.Sprintf("Sending SIG%s to %s...", sig, c.Names)
+					cli := m.clientFor(c)
+					return doAction(func() error {
+						return KillContainer(cli, c.ID, sig)
 					})
 				}
 			}
+			if run != nil {
+				m.openPicker(title, signalOptions, run)
+			}
+
+		case "logs": // on a header, the whole project merged
+			if g, ok := m.currentGroup(); ok {
+				return m, m.openProjectLogs(g)
+			}
+			if c, ok := m.currentContainer(); ok {
+				return m, m.openLogs(c.Host, c.ID)
+			}
+
+		case "details":
+			if c, ok := m.currentContainer(); ok {
+				m.activeView = viewInspect
+				m.inspectID = c.ID
+				m.inspectHost = c.Host
+				m.inspectName = c.Names
+				m.inspectLines = nil
+				m.inspectErr = nil
+				m.inspectOffset = 0
+				return m, fetchInspect(m.clientFor(c), c.ID)
+			}
+
+		case "health":
+			if c, ok := m.currentContainer(); ok {
+				m.activeView = viewHealth
+				m.healthID = c.ID
+				m.healthHost = c.Host
+				m.healthName = c.Names
+				m.healthLines = nil
+				m.healthErr = nil
+				m.healthOffset = 0
+				return m, fetchInspect(m.clientFor(c), c.ID)
+			}
+
+		case "graphs":
+			if c, ok := m.currentContainer(); ok {
+				m.activeView = viewStats
+				m.graphKey = c.key()
+				return m, m.syncStats()
+			}
 
-		case "o": // Open port in browser
-			if m.cursor < len(m.filteredContainers) {
-				c := m.filteredContainers[m.cursor]
+		case "processes":
+			if c, ok := m.currentContainer(); ok {
+				return m, m.openTop(c)
+			}
+
+		case "shell":
+			return m, m.execShell()
+
+		case "exec":
+			m.openExecDialog()
+
+		case "filter":
+			m.filterMode = true
+
+		case "saved-filters":
+			m.openSavedFilters()
+
+		case "open": // first public port, in the browser
+			if c, ok := m.currentContainer(); ok {
 				port := firstPublicPort(c.Ports)
 				if port != "" {
-					url := "http://localhost:" + port
+					url := "http://" + m.portHost(c) + ":" + port
 					m.statusMsg = "Opening " + url
 					return m, func() tea.Msg {
 						exec.Command("xdg-open", url).Start()
@@ -229,14 +798,36 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		return m, m.fitShell()
 
 	case tickMsg:
-		cmds := []tea.Cmd{
-			waitForTick(),
-			fetchContainers(m.dockerClient),
+		cmds := []tea.Cmd{waitForTick()}
+		if m.eventsLive {
+			m.eventsUpFor++
+			if m.eventsUpFor == eventsSettle {
+				m.eventsBackoff = 1
+			}
+		} else {
+			// No event stream, as always in the hosts view: poll, and
+			// retry the subscription with backoff
+			cmds = append(cmds, m.refreshContainers())
+			if m.eventsRetryIn > 0 {
+				m.eventsRetryIn--
+				if m.eventsRetryIn == 0 {
+					m.eventsSession++
+					cmds = append(cmds, subscribeEvents(m.dockerClient, m.eventsSession))
+				}
+			}
+		}
+		cmds = append(cmds, m.syncStats(), m.evaluateAlerts(time.Now()))
+		if m.activeView == viewInspect {
+			cmds = append(cmds, fetchInspect(m.clientOn(m.inspectHost), m.inspectID))
+		}
+		if m.activeView == viewHealth {
+			cmds = append(cmds, fetchInspect(m.clientOn(m.healthHost), m.healthID))
 		}
-		if m.showStats {
-			cmds = append(cmds, fetchAllStats(m.dockerClient, m.filteredContainers))
+		if m.activeView == viewTop {
+			cmds = append(cmds, fetchProcesses(m.clientOn(m.topHost), m.topID))
 		}
 		// Decrement status message countdown
 		if m.statusMsg != "" {
@@ -252,19 +843,110 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, waitForAnimTick()
 
 	case containersMsg:
-		m.allContainers = msg
-		m.filteredContainers = sortAndFilter(m.allContainers, m.sortOrder, m.showAll, m.stats)
-		if m.cursor >= len(m.filteredContainers) && len(m.filteredContainers) > 0 {
-			m.cursor = len(m.filteredContainers) - 1
-		} else if len(m.filteredContainers) == 0 {
-			m.cursor = 0
+		var cmd tea.Cmd
+		if h := m.findHost(msg.cli); h != nil {
+			old := h.containers
+			m.setHostContainers(h, msg.containers)
+			cmd = m.notifyListChanges(msg.cli, old, h.containers)
+		} else if msg.cli == m.dockerClient && m.hosts == nil {
+			delete(m.contextErrs, m.contextName)
+			if !m.eventsLive {
+				// Nothing else reports exits while polling
+				cmd = m.notifyListChanges(msg.cli, m.allContainers, msg.containers)
+			}
+			m.allContainers = msg.containers
+			m.refilter()
+		} else {
+			return m, nil
+		}
+		cmd = tea.Batch(cmd, m.evaluateAlerts(time.Now()))
+		if m.alertsWatch("restarts") {
+			cmd = tea.Batch(cmd, m.fetchRestartCounts(msg.containers))
+		}
+		return m, cmd
+
+	case eventsSubscribedMsg:
+		if msg.stream.session != m.eventsSession {
+			msg.stream.Stop()
+			return m, nil
+		}
+		m.events = msg.stream
+		m.eventsLive = true
+		m.eventsUpFor = 0
+		// Resync once so anything missed while disconnected is picked up
+		return m, tea.Batch(waitForEvent(m.events), fetchContainers(m.dockerClient))
+
+	case containerEventMsg:
+		if m.events == nil || msg.session != m.eventsSession {
+			return m, nil
+		}
+		// An event proves the daemon is really there
+		m.eventsBackoff = 1
+		cmds := []tea.Cmd{waitForEvent(m.events), m.applyContainerEvent(msg.event)}
+		if m.activeView == viewInspect && strings.HasPrefix(msg.event.Actor.ID, m.inspectID) {
+			cmds = append(cmds, fetchInspect(m.clientOn(m.inspectHost), m.inspectID))
+		}
+		if m.activeView == viewHealth && strings.HasPrefix(msg.event.Actor.ID, m.healthID) {
+			cmds = append(cmds, fetchInspect(m.clientOn(m.healthHost), m.healthID))
+		}
+		return m, tea.Batch(cmds...)
+
+	case eventsDroppedMsg:
+		if msg.session != m.eventsSession {
+			return m, nil
+		}
+		m.events.Stop()
+		m.events = nil
+		m.eventsLive = false
+		m.eventsRetryIn = m.eventsBackoff
+		if m.eventsBackoff < eventsRetryMax {
+			m.eventsBackoff *= 2
+		}
+
+	case containerUpdateMsg:
+		if msg.cli != m.dockerClient {
+			return m, nil
+		}
+		if msg.found {
+			m.upsertContainer(msg.container)
+		} else {
+			m.removeContainer(msg.id)
+		}
+		cmd := m.evaluateAlerts(time.Now())
+		if msg.found && m.alertsWatch("restarts") {
+			cmd = tea.Batch(cmd, m.fetchRestartCounts([]Container{msg.container}))
+		}
+		return m, cmd
+
+	case restartCountsMsg:
+		for key, n := range msg {
+			m.restartCounts[key] = n
 		}
+		return m, m.evaluateAlerts(time.Now())
 
 	case statsMsg:
-		m.stats = msg
-		if m.sortOrder == SortByCPU || m.sortOrder == SortByMem {
-			m.filteredContainers = sortAndFilter(m.allContainers, m.sortOrder, m.showAll, m.stats)
+		if msg.session != m.statsSession {
+			return m, nil
+		}
+		m.statsPending = false
+		m.stats = msg.stats
+		m.recordStats(msg.stats)
+		if m.sortOrder.needsStats() {
+			m.refilter()
 		}
+		return m, m.evaluateAlerts(time.Now())
+
+	case oomKilledMsg:
+		return m, m.notify(notifyOOM, msg.c, "out of memory, a process was killed", time.Now())
+
+	case notifyDoneMsg:
+		if msg.err != nil {
+			m.statusMsg = "Notification failed: " + msg.err.Error()
+			m.statusTick = 3
+		}
+
+	case batchItemMsg:
+		return m, m.finishBatchItem(msg)
 
 	case actionMsg:
 		if msg.err != nil {
@@ -273,40 +955,315 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.statusMsg = "Done."
 		}
 		m.statusTick = 3
-		return m, fetchContainers(m.dockerClient)
+		return m, m.refreshContainers()
 
 	case logLinesMsg:
-		m.logLines = msg
-		// Auto-scroll to bottom
-		m.logOffset = len(m.logLines)
+		if msg.session != m.logSession {
+			return m, nil
+		}
+		m.logLines = append(m.logLines, msg.lines...)
+		if over := len(m.logLines) - maxLogLines; over > 0 {
+			m.logLines = m.logLines[over:]
+			if !m.logFollow {
+				m.logOffset -= over
+				if m.logOffset < 0 {
+					m.logOffset = 0
+				}
+			}
+		}
+		if m.logFollow {
+			m.logOffset = m.maxLogOffset()
+		}
+		return m, waitForLogLines(m.logStream)
+
+	case logStreamEndMsg:
+		if msg.session == m.logSession {
+			m.logStream = nil
+			m.logStreamErr = msg.err
+		}
+
+	case inspectMsg:
+		if m.activeView == viewHealth && msg.id == m.healthID {
+			m.healthErr = msg.err
+			if msg.err == nil {
+				m.healthLines = healthLines(msg.info)
+				m.healthOffset = min(m.healthOffset, m.maxHealthOffset())
+			}
+			return m, nil
+		}
+		if m.activeView != viewInspect || msg.id != m.inspectID {
+			return m, nil
+		}
+		m.inspectErr = msg.err
+		if msg.err == nil {
+			m.inspectLines = inspectLines(msg.info)
+			if m.inspectOffset > m.maxInspectOffset() {
+				m.inspectOffset = m.maxInspectOffset()
+			}
+		}
+
+	case processesMsg:
+		if m.activeView != viewTop || msg.id != m.topID {
+			return m, nil
+		}
+		m.processesErr = msg.err
+		if msg.err == nil {
+			m.setProcesses(msg.procs)
+		}
+
+	case processActionMsg:
+		if msg.err != nil {
+			m.statusMsg = "Error: " + msg.err.Error()
+		} else {
+			m.statusMsg = msg.done
+		}
+		m.statusTick = 3
+		return m, fetchProcesses(m.clientOn(m.topHost), m.topID)
+
+	case imagesMsg:
+		if !m.onCurrentDaemon(msg.cli) {
+			return m, nil
+		}
+		m.imagesErr = msg.err
+		if msg.err == nil {
+			m.images = msg.images
+			sortImages(m.images, m.imageSort, imageUsage(m.allContainers))
+			m.moveImageCursor(0)
+		}
+
+	case imageActionMsg:
+		if !m.onCurrentDaemon(msg.cli) {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.statusMsg = "Error: " + msg.err.Error()
+		} else {
+			m.statusMsg = msg.done
+		}
+		m.statusTick = 3
+		return m, fetchImages(m.dockerClient)
+
+	case imagePrunePreviewMsg:
+		if !m.onCurrentDaemon(msg.cli) {
+			return m, nil
+		}
+		m.statusMsg = ""
+		switch {
+		case msg.err != nil:
+			m.statusMsg = "Error: " + msg.err.Error()
+			m.statusTick = 3
+		case msg.count == 0:
+			m.statusMsg = "No dangling images to prune."
+			m.statusTick = 3
+		default:
+			m.confirmMode = true
+			m.confirmAction = "prune-images"
+			m.confirmText = fmt.Sprintf("Prune %d dangling image(s) and reclaim up to %s?", msg.count, formatBytes(float64(msg.size)))
+		}
+
+	case pullProgressMsg:
+		if m.pull == nil || msg.session != m.pull.session {
+			return m, nil
+		}
+		for _, pm := range msg.messages {
+			m.pull.apply(pm)
+		}
+		return m, waitForPullProgress(m.pull)
+
+	case pullDoneMsg:
+		if m.pull == nil || msg.session != m.pull.session || !m.onCurrentDaemon(msg.cli) {
+			return m, nil
+		}
+		ref := m.pull.ref
+		m.cancelPull() // releases the context
+		if msg.err != nil {
+			m.statusMsg = "Pull of " + ref + " failed: " + msg.err.Error()
+		} else {
+			m.statusMsg = "Pulled " + ref + "."
+		}
+		m.statusTick = 3
+		return m, fetchImages(m.dockerClient)
+
+	case volumesMsg:
+		if !m.onCurrentDaemon(msg.cli) {
+			return m, nil
+		}
+		m.volumesErr = msg.err
+		if msg.err == nil {
+			m.volumes = msg.volumes
+			m.moveVolumeCursor(0)
+		}
+
+	case volumeActionMsg:
+		if !m.onCurrentDaemon(msg.cli) {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.statusMsg = "Error: " + msg.err.Error()
+		} else {
+			m.statusMsg = msg.done
+		}
+		m.statusTick = 3
+		return m, fetchVolumes(m.dockerClient)
+
+	case networksMsg:
+		if !m.onCurrentDaemon(msg.cli) {
+			return m, nil
+		}
+		m.networksErr = msg.err
+		if msg.err == nil {
+			m.networks = msg.networks
+			m.moveNetworkCursor(0)
+		}
+
+	case networkActionMsg:
+		if !m.onCurrentDaemon(msg.cli) {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.statusMsg = "Error: " + msg.err.Error()
+		} else {
+			m.statusMsg = msg.done
+		}
+		m.statusTick = 3
+		// Connect and disconnect change the containers' addresses too
+		return m, tea.Batch(fetchNetworks(m.dockerClient), fetchContainers(m.dockerClient))
 
-	case execDoneMsg:
-		return m, fetchContainers(m.dockerClient)
+	case shellStartedMsg:
+		if !m.shellsStarting[msg.key] {
+			// Started for a daemon that has since been left
+			if msg.s != nil {
+				msg.s.Close()
+			}
+			return m, nil
+		}
+		delete(m.shellsStarting, msg.key)
+		if msg.err != nil {
+			m.statusMsg = "Shell failed: " + msg.err.Error()
+			if errors.Is(msg.err, errNoShell) {
+				m.statusMsg += fmt.Sprintf("; it may be a distroless image, %s runs another command", m.keys.keysFor("containers", "exec"))
+			}
+			m.statusTick = 3
+			return m, nil
+		}
+		m.statusMsg = ""
+		m.shells[msg.s.key] = msg.s
+		return m, tea.Batch(m.attachShell(msg.s), waitForShellOutput(msg.s))
+
+	case shellOutputMsg:
+		// Keep reading while the session is open; the redraw is the point
+		if m.shells[msg.s.key] == msg.s {
+			return m, waitForShellOutput(msg.s)
+		}
+
+	case shellEndedMsg:
+		m.endShell(msg.s)
+
+	case configSavedMsg:
+		if msg.err != nil {
+			m.statusMsg = "Not saved: " + msg.err.Error()
+		} else {
+			m.statusMsg = msg.done
+		}
+		m.statusTick = 3
 
 	case openBrowserMsg:
 		// nothing to do
 
-	case errMsg:
-		m.err = msg.err
+	case connErrMsg:
+		if h := m.findHost(msg.cli); h != nil {
+			h.pending, h.err = false, msg.err
+		} else if msg.cli == m.dockerClient && m.hosts == nil {
+			m.contextErrs[m.contextName] = msg.err
+		}
 	}
 
 	return m, nil
 }
 
-type statsMsg map[string]Stats
+// switchView opens one of the resource screens and loads its data.
+func (m *model) switchView(v ActiveView) tea.Cmd {
+	if m.hosts != nil {
+		m.statusMsg = fmt.Sprintf("The resource screens are per daemon; %s leaves the hosts view", m.keys.keysFor("containers", "hosts"))
+		m.statusTick = 3
+		return nil
+	}
+	if m.dockerClient == nil {
+		m.statusMsg = fmt.Sprintf("Not connected to context %s", m.contextName)
+		m.statusTick = 3
+		return nil
+	}
+	m.activeView = v
+	switch v {
+	case viewImages:
+		m.imageList = listState{}
+		return fetchImages(m.dockerClient)
+	case viewVolumes:
+		m.volumeList = listState{}
+		return fetchVolumes(m.dockerClient)
+	case viewNetworks:
+		m.networkList = listState{}
+		return fetchNetworks(m.dockerClient)
+	}
+	return nil
+}
+
+// scrollToCursor moves the table window so the cursor row is visible.
+func (m *model) scrollToCursor() {
+	headerHeight := 10
+	footerHeight := 2
+	if m.filterBarShown() {
+		footerHeight++
+	}
+	tableHeight := m.height - headerHeight - footerHeight
+	if tableHeight < 1 {
+		tableHeight = 1
+	}
+	if m.cursor < m.tableOffset {
+		m.tableOffset = m.cursor
+	}
+	if m.cursor >= m.tableOffset+tableHeight {
+		m.tableOffset = m.cursor - tableHeight + 1
+	}
+}
 
-func fetchAllStats(cli *client.Client, containers []Container) tea.Cmd {
-	return func() tea.Msg {
-		results := make(map[string]Stats)
-		for _, c := range containers {
-			if strings.HasPrefix(strings.ToLower(c.Status), "up") {
-				stats, err := GetContainerStats(cli, c.ID)
-				if err == nil {
-					results[c.ID] = stats
-				}
-			}
-		}
-		return statsMsg(results)
+// openLogs switches to the log viewer and starts following container id
+// of the named host, "" outside the hosts view.
+func (m *model) openLogs(host, id string) tea.Cmd {
+	c := Container{ID: id, Host: host}
+	return m.startLogs(c.key(), []logTarget{{cli: m.clientFor(c), id: id}})
+}
+
+// startLogs switches to the log viewer and follows every target in one
+// merged stream.
+func (m *model) startLogs(title string, targets []logTarget) tea.Cmd {
+	m.closeLogs()
+	m.activeView = viewLogs
+	m.logTitle = title
+	m.logFollow = true
+	m.logStream = newLogStream(m.logSession)
+	return tea.Batch(
+		followLogs(m.logStream, targets...),
+		waitForLogLines(m.logStream),
+	)
+}
+
+// scrollLogs moves the log viewport by delta lines. Scrolling up releases the
+// tail lock; scrolling back down to the last line re-engages it.
+func (m *model) scrollLogs(delta int) {
+	max := m.maxLogOffset()
+	if m.logOffset > max {
+		m.logOffset = max
+	}
+	m.logOffset += delta
+	if m.logOffset < 0 {
+		m.logOffset = 0
+	}
+	if m.logOffset >= max {
+		m.logOffset = max
+		m.logFollow = true
+	} else {
+		m.logFollow = false
 	}
 }
 
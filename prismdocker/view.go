@@ -2,7 +2,9 @@ package main
 
 import (
 	"fmt"
+	"hash/fnv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 )
@@ -16,14 +18,50 @@ const (
 )
 
 func (m model) View() string {
-	if m.err != nil {
-		return fmt.Sprintf("Error: %v", m.err)
+	// Dispatch to the active screen
+	var base string
+	switch m.activeView {
+	case viewLogs:
+		base = m.renderLogsView()
+	case viewInspect:
+		base = m.renderInspectView()
+	case viewImages:
+		base = m.renderImagesView()
+	case viewVolumes:
+		base = m.renderVolumesView()
+	case viewNetworks:
+		base = m.renderNetworksView()
+	case viewStats:
+		base = m.renderStatsView()
+	case viewTop:
+		base = m.renderTopView()
+	case viewHealth:
+		base = m.renderHealthView()
+	case viewShell:
+		base = m.renderShellView()
+	default:
+		base = m.renderContainersView()
 	}
 
-	// Dispatch to log viewer
-	if m.activeView == viewLogs {
-		return m.renderLogsView()
+	// Overlay popups if needed
+	if m.batchSummary && m.batch != nil {
+		return renderMessagePopup(base, m.width, m.height, m.batch.summary())
+	}
+	if m.confirmMode {
+		answers := fmt.Sprintf("[%s] Yes    [%s] No", m.keys.keysFor("confirm", "yes"), m.keys.keysFor("confirm", "no"))
+		return renderConfirmPopup(base, m.width, m.height, m.confirmText, answers)
 	}
+	if m.picker != nil {
+		return m.picker.render(m.width, m.height)
+	}
+	if m.execDialog != nil {
+		return m.execDialog.render(m.width, m.height)
+	}
+	return base
+}
+
+// renderContainersView renders the main container table.
+func (m model) renderContainersView() string {
 	// Calculate dynamic widths based on terminal width
 	// Total available width roughly: m.width - 4 (borders/padding)
 	// We want to ensure at least some view.
@@ -33,16 +71,36 @@ func (m model) View() string {
 	}
 
 	// Dynamic Widths
-	var wID, wName, wImage, wStatus, wPorts, wCPU, wMem, wNet int
+	var wID, wName, wImage, wStatus, wPorts, wCPU, wMem, wNet, wBlk, wPIDs int
+	showSpark := false
+	wHealth := 13
+	// Host column, in the hosts view only; room for the stale mark too
+	wHost := 0
+	if m.hosts != nil {
+		wHost = 6
+		for _, h := range m.hosts {
+			wHost = max(wHost, len(h.name)+4)
+		}
+		wHost = min(wHost, 20)
+	}
 
 	if m.showStats {
 		wID = 15
 		wStatus = 12
-		wCPU = 20
-		wMem = 20
-		wNet = 20
-
-		available := availableWidth - wID - wStatus - wCPU - wMem - wNet
+		wCPU = 19
+		wMem = 23
+		wNet = 18
+		wBlk = 18
+		wPIDs = 6
+
+		// Sparklines only when the names can still get a decent width
+		available := availableWidth - wID - wHost - wStatus - wHealth - wCPU - wMem - wNet - wBlk - wPIDs
+		if available >= 2*(sparkWidth+1)+40 {
+			showSpark = true
+			wCPU += sparkWidth + 1
+			wMem += sparkWidth + 1
+			available -= 2 * (sparkWidth + 1)
+		}
 		if available < 20 {
 			available = 20
 		}
@@ -54,7 +112,7 @@ func (m model) View() string {
 		// Standard View
 		wID = 15
 		wStatus = 20
-		remaining := availableWidth - wID - wStatus
+		remaining := availableWidth - wID - wHost - wStatus - wHealth
 		if remaining < 0 {
 			remaining = 0
 		}
@@ -88,22 +146,23 @@ func (m model) View() string {
 	//     \   / === [ ]
 	//      \ /  === [ ]
 
-	lightColor := lipgloss.Color("255") // White
-	prismColor := lipgloss.Color("63")  // Blue-ish/Cyan
+	lightColor := lipgloss.Color(activeTheme.Text)
+	prismColor := lipgloss.Color(activeTheme.Info)
 
 	// Beams (Rainbow)
-	beam1 := lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("~") // Red
-	beam2 := lipgloss.NewStyle().Foreground(lipgloss.Color("208")).Render("~") // Orange
-	beam3 := lipgloss.NewStyle().Foreground(lipgloss.Color("226")).Render("~") // Yellow
-	beam4 := lipgloss.NewStyle().Foreground(lipgloss.Color("46")).Render("~")  // Green
-	beam5 := lipgloss.NewStyle().Foreground(lipgloss.Color("21")).Render("~")  // Blue
-	beam6 := lipgloss.NewStyle().Foreground(lipgloss.Color("51")).Render("~")  // Cyan
-	beam7 := lipgloss.NewStyle().Foreground(lipgloss.Color("129")).Render("~") // Violet
+	beams := activeTheme.Beams
+	beam1 := lipgloss.NewStyle().Foreground(lipgloss.Color(beams[0])).Render("~") // Red
+	beam2 := lipgloss.NewStyle().Foreground(lipgloss.Color(beams[1])).Render("~") // Orange
+	beam3 := lipgloss.NewStyle().Foreground(lipgloss.Color(beams[2])).Render("~") // Yellow
+	beam4 := lipgloss.NewStyle().Foreground(lipgloss.Color(beams[3])).Render("~") // Green
+	beam5 := lipgloss.NewStyle().Foreground(lipgloss.Color(beams[4])).Render("~") // Blue
+	beam6 := lipgloss.NewStyle().Foreground(lipgloss.Color(beams[5])).Render("~") // Cyan
+	beam7 := lipgloss.NewStyle().Foreground(lipgloss.Color(beams[6])).Render("~") // Violet
 
 	// Blocks (Containers)
-	box1 := lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("[]")
-	box2 := lipgloss.NewStyle().Foreground(lipgloss.Color("46")).Render("[]")
-	box3 := lipgloss.NewStyle().Foreground(lipgloss.Color("21")).Render("[]")
+	box1 := lipgloss.NewStyle().Foreground(lipgloss.Color(beams[0])).Render("[]")
+	box2 := lipgloss.NewStyle().Foreground(lipgloss.Color(beams[3])).Render("[]")
+	box3 := lipgloss.NewStyle().Foreground(lipgloss.Color(beams[4])).Render("[]")
 
 	// Prism Shape
 	pTop := lipgloss.NewStyle().Foreground(prismColor).Render("      / \\")
@@ -143,15 +202,24 @@ func (m model) View() string {
  / ____/ /  / (__  ) / / / / /   
 /_/   /_/  /_/____/_/ /_/ /_/    `
 
-	title := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("205")).
-		Bold(true).
-		Render(titleText)
+	title := titleStyle.Render(titleText)
 
 	// Combine Prism + Title
 	fullLogo := lipgloss.JoinHorizontal(lipgloss.Bottom, prismLogo, "   ", title)
 
-	stats := fmt.Sprintf("Running: %d | Total: %d", runningCount, len(m.allContainers))
+	stats := mutedStyle.Render("Context: " + m.contextName)
+	if m.contextErr() != nil {
+		stats = alertStyle.Render("Context: " + m.contextName + " ✖")
+	}
+	if m.hosts != nil {
+		hosts := fmt.Sprintf("Hosts: %d/%d up", m.hostsUp(), len(m.hosts))
+		if m.hostsUp() < len(m.hosts) {
+			stats = alertStyle.Render(hosts)
+		} else {
+			stats = mutedStyle.Render(hosts)
+		}
+	}
+	stats += mutedStyle.Render(fmt.Sprintf(" | Running: %d | Total: %d", runningCount, len(m.allContainers)))
 
 	showStatus := "All"
 	if !m.showAll {
@@ -162,11 +230,25 @@ func (m model) View() string {
 	if m.showStats {
 		statsLabel = "ON"
 	}
-	statusInfo := fmt.Sprintf("Sort: %s | Show: %s | Stats: %s", m.sortOrder, showStatus, statsLabel)
+	eventsLabel := "live"
+	if !m.eventsLive {
+		eventsLabel = "polling"
+	}
+	groupLabel := "OFF"
+	if m.groupByProject {
+		groupLabel = "ON"
+	}
+	statusInfo := fmt.Sprintf("Sort: %s | Show: %s | Group: %s | Stats: %s | Events: %s", m.sortOrder, showStatus, groupLabel, statsLabel, eventsLabel)
+	if n := len(m.selected); n > 0 {
+		statusInfo = fmt.Sprintf("Selected: %d | ", n) + statusInfo
+	}
+	if n := len(m.shells); n > 0 {
+		statusInfo = fmt.Sprintf("Shells: %d | ", n) + statusInfo
+	}
 
 	metaInfo := lipgloss.JoinVertical(lipgloss.Right,
-		lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(stats),
-		lipgloss.NewStyle().Foreground(lipgloss.Color("63")).Render(statusInfo),
+		stats,
+		infoStyle.Render(statusInfo),
 	)
 
 	headerContent := lipgloss.JoinHorizontal(lipgloss.Bottom,
@@ -177,23 +259,32 @@ func (m model) View() string {
 	header := ListHeaderStyle.Render(headerContent)
 
 	// Table Header
-	var tHeaderCols string
+	var tHeaderCols, hostHeader string
+	if wHost > 0 {
+		hostHeader = ListItemStyle.Width(wHost).Render("Host")
+	}
 	if m.showStats {
 		tHeaderCols = lipgloss.JoinHorizontal(lipgloss.Top,
 			ListItemStyle.Width(wID).Render("ID"),
+			hostHeader,
 			ListItemStyle.Width(wName).Render("Name"),
 			ListItemStyle.Width(wImage).Render("Image"),
 			ListItemStyle.Width(wStatus).Render("Status"),
+			ListItemStyle.Width(wHealth).Render("Health"),
 			ListItemStyle.Width(wCPU).Render("CPU%"),
 			ListItemStyle.Width(wMem).Render("MEM"),
-			ListItemStyle.Width(wNet).Render("NET I/O"),
+			ListItemStyle.Width(wNet).Render("NET ↓/↑"),
+			ListItemStyle.Width(wBlk).Render("BLK R/W"),
+			ListItemStyle.Width(wPIDs).Render("PIDS"),
 		)
 	} else {
 		tHeaderCols = lipgloss.JoinHorizontal(lipgloss.Top,
 			ListItemStyle.Width(wID).Render("ID"),
+			hostHeader,
 			ListItemStyle.Width(wName).Render("Name"),
 			ListItemStyle.Width(wImage).Render("Image"),
 			ListItemStyle.Width(wStatus).Render("Status"),
+			ListItemStyle.Width(wHealth).Render("Health"),
 			ListItemStyle.Width(wPorts).Render("Ports"),
 		)
 	}
@@ -201,8 +292,10 @@ func (m model) View() string {
 
 	// Footer definition (moved up for height interp)
 	// Footer
-	footerText := "↑/k↓/j: Nav • r: Refresh • s: Sort • a: All/Running • t: Stats • S: Stop • u: Start • R: Restart • x: Remove • l: Logs • i: Shell • o: Open • q: Quit"
-	if m.statusMsg != "" {
+	footerText := m.keys.footer("containers")
+	if m.batch.running() {
+		footerText = m.batch.progress()
+	} else if m.statusMsg != "" {
 		footerText = m.statusMsg
 	}
 	footer := helpStyle.Render(footerText)
@@ -213,6 +306,12 @@ func (m model) View() string {
 	footerH := lipgloss.Height(footer)
 
 	bodyHeight := m.height - headerH - tHeaderH - footerH
+	if m.prompt != nil {
+		bodyHeight--
+	}
+	if m.filterBarShown() {
+		bodyHeight--
+	}
 	if bodyHeight < 1 {
 		bodyHeight = 1
 	}
@@ -220,21 +319,50 @@ func (m model) View() string {
 	// Viewport Slicing
 	start := m.tableOffset
 	end := start + bodyHeight
-	if end > len(m.filteredContainers) {
-		end = len(m.filteredContainers)
+	if end > len(m.rows) {
+		end = len(m.rows)
+	}
+	rowWidth := 2 + wID + wHost + wName + wImage + wStatus + wHealth
+	if m.showStats {
+		rowWidth += wCPU + wMem + wNet + wBlk + wPIDs
+	} else {
+		rowWidth += wPorts
 	}
 
 	// Table Rows
 	var rows []string
 
-	if len(m.filteredContainers) == 0 {
-		rows = append(rows, "No containers found.")
+	if len(m.rows) == 0 {
+		var unreachable []string
+		if len(m.allContainers) == 0 {
+			if err := m.contextErr(); err != nil && m.hosts == nil {
+				unreachable = append(unreachable, fmt.Sprintf("Can't reach context %s: %v", m.contextName, err))
+			}
+			for _, h := range m.hosts {
+				if h.err != nil {
+					unreachable = append(unreachable, fmt.Sprintf("Can't reach host %s: %v", h.name, h.err))
+				}
+			}
+		}
+		if len(unreachable) > 0 {
+			for _, line := range unreachable {
+				rows = append(rows, alertStyle.Render(line))
+			}
+		} else if m.filter.active() {
+			rows = append(rows, "No containers match the filter.")
+		} else {
+			rows = append(rows, "No containers found.")
+		}
 	} else {
 		for i := start; i < end; i++ {
-			c := m.filteredContainers[i]
-			cursor := "  "
+			if g := m.rows[i].group; g != nil {
+				rows = append(rows, m.renderGroupHeader(g, m.cursor == i, rowWidth))
+				continue
+			}
+			c := m.rows[i].container
+			cursor := " " + m.rowMark(c.key())
 			if m.cursor == i {
-				cursor = "> "
+				cursor = ">" + m.rowMark(c.key())
 			}
 
 			// Style (Zebra + Selection)
@@ -242,34 +370,64 @@ func (m model) View() string {
 			if m.cursor == i {
 				style = selectedStyle
 			} else if i%2 == 0 {
-				style = style.Copy().Background(lipgloss.Color("235")) // Zebra stripe
+				style = style.Copy().Background(rowEvenBg) // Zebra stripe
+			}
+			// Alert rules from the config, memory > 80% / > 95% by default
+			switch m.alerts[c.key()] {
+			case alertBad:
+				style = style.Copy().Background(lipgloss.Color(activeTheme.Bad))
+			case alertWarn:
+				style = style.Copy().Background(lipgloss.Color(activeTheme.Warn))
 			}
 
 			status := minifyStatus(c.Status)
-			if c.State == "running" {
+			switch c.State {
+			case "running":
 				status = statusUpStyle.Render(status)
-			} else {
+			case "paused":
+				status = statusPausedStyle.Render(status)
+			default:
 				status = statusExitedStyle.Render(status)
 			}
+			// Rows of an unreachable host show what it last said
+			stale := m.hostErr(c.Host) != nil
+			if stale {
+				status = mutedStyle.Render(truncate("stale: "+minifyStatus(c.Status), wStatus-2))
+			}
 
 			// Truncate/Scroll logic
 			padding := 2
 
 			isSelected := m.cursor == i
 
+			// Filter matches are picked out like the ID and image colours,
+			// on every row but the selected one
+			var hl filterHighlights
+			if m.filter.active() {
+				hl = m.filter.highlights(c)
+			}
+
 			// ID Style
 			idVal := c.ID
 			if !isSelected {
-				idVal = idStyle.Render(idVal)
+				idVal = highlightText(idVal, c.ID, hl.id, idStyle)
 			}
 			id := style.Width(wID).Render(idVal)
 
+			var host string
+			if wHost > 0 {
+				host = style.Width(wHost).Render(truncate(c.Host, wHost-padding))
+				if stale {
+					host = style.Width(wHost).Render(alertStyle.Render("✖ " + truncate(c.Host, wHost-padding-2)))
+				}
+			}
+
 			// Name: Scroll effectively
 			nameRaw := c.Names
 			if isSelected {
 				nameRaw = scrollText(nameRaw, wName-padding, m.tick)
 			} else {
-				nameRaw = truncate(nameRaw, wName-padding)
+				nameRaw = highlightText(truncate(nameRaw, wName-padding), c.Names, hl.name, lipgloss.NewStyle())
 			}
 			name := style.Width(wName).Render(nameRaw)
 
@@ -278,51 +436,65 @@ func (m model) View() string {
 			if isSelected {
 				imageRaw = scrollText(imageRaw, wImage-padding, m.tick)
 			} else {
-				imageRaw = truncate(imageRaw, wImage-padding)
-				if !isSelected {
-					imageRaw = imageStyle.Render(imageRaw)
-				}
+				imageRaw = highlightText(truncate(imageRaw, wImage-padding), c.Image, hl.image, imageStyle)
 			}
 			image := style.Width(wImage).Render(imageRaw)
 
 			stat := style.Width(wStatus).Render(status)
+			health := style.Width(wHealth).Render(renderHealth(c.Health))
 
 			var row string
 			if m.showStats {
 				// Stats columns with progress bars
-				s := m.stats[c.ID]
-				var cpuStr, memStr, netStr string
+				s := m.stats[c.key()]
+				hist := m.statsHistory[c.key()]
+				var cpuStr, memStr, netStr, blkStr, pidsStr string
 				if c.State != "running" {
 					cpuStr = "-"
 					memStr = "-"
 					netStr = "-"
+					blkStr = "-"
+					pidsStr = "-"
 				} else {
-					cpuStr = renderBar(s.CPUPercent, 8) + fmt.Sprintf(" %.1f%%", s.CPUPercent)
+					cpuStr = renderBar(s.CPUPercent, 8) + fmt.Sprintf(" %5.1f%%", s.CPUPercent)
+					if showSpark {
+						cpu := hist.series(func(s Stats) float64 { return s.CPUPercent })
+						_, cpuMax := seriesRange(cpu)
+						cpuStr += " " + sparkline(cpu, sparkWidth, 0, max(cpuMax, 1))
+					}
 					memPct := 0.0
 					if s.MemLimit > 0 {
 						memPct = s.MemUsage / s.MemLimit * 100
 					}
-					// Alert: yellow >80%, red >95%
-					if memPct > 95 {
-						style = style.Copy().Background(lipgloss.Color("196"))
-					} else if memPct > 80 {
-						style = style.Copy().Background(lipgloss.Color("214"))
+					memStr = renderBar(memPct, 8) + fmt.Sprintf(" %11s", formatBytesShort(s.MemUsage)+"/"+formatBytesShort(s.MemLimit))
+					if showSpark {
+						// Scaled to the window's own range so a slow climb stands out
+						mem := hist.series(func(s Stats) float64 { return s.MemUsage })
+						memLo, memHi := seriesRange(mem)
+						memStr += " " + sparkline(mem, sparkWidth, memLo, memHi)
 					}
-					memStr = renderBar(memPct, 8) + fmt.Sprintf(" %s/%s", formatBytesShort(s.MemUsage), formatBytesShort(s.MemLimit))
-					netStr = fmt.Sprintf("%s↑%s↓", formatBytesShort(s.NetTx), formatBytesShort(s.NetRx))
+					netStr = fmt.Sprintf("↓%-7s ↑%s", formatBytesShort(s.NetRxRate)+"/s", formatBytesShort(s.NetTxRate)+"/s")
+					blkStr = fmt.Sprintf("R%-7s W%s", formatBytesShort(s.BlkReadRate)+"/s", formatBytesShort(s.BlkWriteRate)+"/s")
+					pidsStr = fmt.Sprintf("%d", s.PIDs)
 				}
 				cpuCol := style.Width(wCPU).Render(cpuStr)
 				memCol := style.Width(wMem).Render(memStr)
 				netCol := style.Width(wNet).Render(netStr)
+				blkCol := style.Width(wBlk).Render(blkStr)
+				pidsCol := style.Width(wPIDs).Render(pidsStr)
 				row = lipgloss.JoinHorizontal(lipgloss.Top,
 					style.Render(cursor),
 					id,
+					host,
 					name,
 					image,
 					stat,
+					health,
 					cpuCol,
 					memCol,
 					netCol,
+					blkCol,
+					pidsCol,
 				)
 			} else {
 				// Ports: Discrete Scroll
@@ -339,9 +511,11 @@ func (m model) View() string {
 				row = lipgloss.JoinHorizontal(lipgloss.Top,
 					style.Render(cursor),
 					id,
+					host,
 					name,
 					image,
 					stat,
+					health,
 					finalPort,
 				)
 			}
@@ -360,18 +534,14 @@ func (m model) View() string {
 	body := lipgloss.JoinVertical(lipgloss.Left, rows...)
 
 	// Base view
-	base := lipgloss.JoinVertical(lipgloss.Left,
-		header,
-		tHeader,
-		body,
-		footer,
-	)
-
-	// Overlay confirm popup if needed
-	if m.confirmMode {
-		return renderConfirmPopup(base, m.width, m.height)
+	parts := []string{header, tHeader, body}
+	if m.filterBarShown() {
+		parts = append(parts, m.renderFilterBar())
 	}
-	return base
+	if m.prompt != nil {
+		parts = append(parts, m.prompt.render())
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, append(parts, footer)...)
 }
 
 func formatPorts(p string) string {
@@ -519,6 +689,22 @@ func formatBytesShort(b float64) string {
 	return fmt.Sprintf("%.1f%c", val, "KMGTP"[exp])
 }
 
+// formatAge renders a duration the way minifyStatus does: 45s, 12m, 3h, 5d, 2w.
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d < 14*24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	default:
+		return fmt.Sprintf("%dw", int(d.Hours()/24/7))
+	}
+}
+
 // minifyStatus shortens Docker status strings for compact display.
 // e.g. "Up 3 hours" -> "Up 3h", "Exited (0) 2 days ago" -> "Exit 2d"
 func minifyStatus(s string) string {
@@ -536,6 +722,10 @@ func minifyStatus(s string) string {
 		" months", "mo",
 		" month", "mo",
 		" ago", "",
+		// Health has a column of its own
+		" (healthy)", "",
+		" (unhealthy)", "",
+		" (health: starting)", "",
 		"Exited", "Exit",
 		"Created", "New",
 		"Restarting", "Restart",
@@ -555,62 +745,55 @@ func renderBar(pct float64, width int) string {
 	filled := int(pct / 100 * float64(width))
 	bar := strings.Repeat("■", filled) + strings.Repeat("□", width-filled)
 
-	var color lipgloss.Color
+	var color string
 	switch {
 	case pct > 95:
-		color = lipgloss.Color("196") // Red
+		color = activeTheme.Bad
 	case pct > 80:
-		color = lipgloss.Color("214") // Orange
+		color = activeTheme.Warn
 	case pct > 60:
-		color = lipgloss.Color("226") // Yellow
+		color = activeTheme.Caution
 	default:
-		color = lipgloss.Color("46") // Green
+		color = activeTheme.Good
 	}
-	return lipgloss.NewStyle().Foreground(color).Render("[" + bar + "]")
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render("[" + bar + "]")
 }
 
 // renderLogsView renders the full-screen log viewer.
 func (m model) renderLogsView() string {
-	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
-	title := titleStyle.Render(fmt.Sprintf("Logs: %s", m.logContainer))
+	title := titleStyle.Render(fmt.Sprintf("Logs: %s", m.logTitle))
+
+	// Stream state next to the title
+	var state string
+	switch {
+	case m.logStream == nil && m.logStreamErr != nil:
+		state = statusExitedStyle.Render("✖ stream error: " + m.logStreamErr.Error())
+	case m.logStream == nil:
+		state = helpStyle.Copy().MarginTop(0).Render("■ stream closed")
+	case m.logFollow:
+		state = statusUpStyle.Render("● following")
+	default:
+		state = warnStyle.Render(fmt.Sprintf("❚❚ paused — %s to follow", m.keys.keysFor("logs", "follow")))
+	}
+	title = title + "  " + state + "  " +
+		infoStyle.Render("Streams: "+m.logSources.String())
 
-	filterStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("63"))
+	filterStyle := infoStyle
 	filterBar := ""
 	if m.logFilterMode {
 		filterBar = filterStyle.Render(fmt.Sprintf("Filter: %s█", m.logFilter))
 	} else if m.logFilter != "" {
-		filterBar = filterStyle.Render(fmt.Sprintf("Filter: %s  (/ to edit)", m.logFilter))
+		filterBar = filterStyle.Render(fmt.Sprintf("Filter: %s  (%s to edit)", m.logFilter, m.keys.keysFor("logs", "filter")))
 	}
 
-	footerStr := "Esc/q: Back • /: Filter • ↑/k↓/j: Scroll"
-	footer := helpStyle.Render(footerStr)
+	footer := helpStyle.Render(m.keys.footer("logs"))
 
-	headerH := lipgloss.Height(title) + 1
-	footerH := lipgloss.Height(footer)
-	filterH := 0
-	if filterBar != "" {
-		filterH = 1
-	}
-	bodyH := m.height - headerH - footerH - filterH - 1
-	if bodyH < 1 {
-		bodyH = 1
-	}
-
-	// Filter lines
-	lines := m.logLines
-	if m.logFilter != "" {
-		var filtered []string
-		for _, l := range lines {
-			if strings.Contains(strings.ToLower(l), strings.ToLower(m.logFilter)) {
-				filtered = append(filtered, l)
-			}
-		}
-		lines = filtered
-	}
+	bodyH := m.logBodyHeight()
+	lines := m.visibleLogLines()
 
 	// Scroll
 	offset := m.logOffset
-	if offset > len(lines)-bodyH {
+	if m.logFollow || offset > len(lines)-bodyH {
 		offset = len(lines) - bodyH
 	}
 	if offset < 0 {
@@ -620,7 +803,20 @@ func (m model) renderLogsView() string {
 	if end > len(lines) {
 		end = len(lines)
 	}
-	visible := lines[offset:end]
+	// Line up merged logs behind the widest service name on screen
+	prefixW := 0
+	for _, l := range lines[offset:end] {
+		if w := lipgloss.Width(l.Prefix); w > prefixW {
+			prefixW = w
+		}
+	}
+	var visible []string
+	for _, l := range lines[offset:end] {
+		visible = append(visible, renderLogLine(l, prefixW))
+	}
+	if len(m.logLines) == 0 && m.logStream == nil && m.logStreamErr == nil {
+		visible = []string{"(no logs)"}
+	}
 
 	// Pad
 	for len(visible) < bodyH {
@@ -637,30 +833,54 @@ func (m model) renderLogsView() string {
 	return lipgloss.JoinVertical(lipgloss.Left, parts...)
 }
 
-// renderConfirmPopup overlays a centered confirmation dialog on top of the base view.
-func renderConfirmPopup(base string, width, height int) string {
+// renderLogLine draws one log line with a gutter marking its stream.
+// stderr lines are tinted so errors stand out from regular output. In
+// merged logs the service name comes first, padded to prefixW and coloured
+// per service.
+func renderLogLine(l logLine, prefixW int) string {
+	prefix := ""
+	if prefixW > 0 {
+		prefix = logPrefixStyle(l.Prefix).Render(fmt.Sprintf("%-*s", prefixW, l.Prefix)) + " "
+	}
+	if l.Source == sourceStderr {
+		return prefix + logStderrGutterStyle.Render("┃ ") + logStderrStyle.Render(l.Text)
+	}
+	return prefix + logStdoutGutterStyle.Render("│ ") + l.Text
+}
+
+// logPrefixStyle picks a stable colour for a service name.
+func logPrefixStyle(name string) lipgloss.Style {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return lipgloss.NewStyle().Foreground(logPrefixColors[h.Sum32()%uint32(len(logPrefixColors))])
+}
+
+// renderConfirmPopup overlays a centered confirmation dialog on top of the
+// base view. answers says which keys confirm and cancel.
+func renderConfirmPopup(base string, width, height int, question, answers string) string {
+	msg := alertStyle.Render("⚠  "+question) +
+		"\n\n" +
+		textStyle.Render("  "+answers)
+	return renderPopup(width, height, msg)
+}
+
+// renderMessagePopup overlays a centered notice that any key dismisses.
+func renderMessagePopup(base string, width, height int, text string) string {
+	msg := alertStyle.Render(text) +
+		"\n\n" +
+		textStyle.Render("  Press any key to dismiss")
+	return renderPopup(width, height, msg)
+}
+
+// renderPopup draws a bordered box centered in a width x height area.
+func renderPopup(width, height int, msg string) string {
 	popupStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("196")).
+		BorderForeground(lipgloss.Color(activeTheme.Bad)).
 		Padding(1, 4).
 		Bold(true)
 
-	msg := lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("⚠  Remove this container?") +
-		"\n\n" +
-		lipgloss.NewStyle().Foreground(lipgloss.Color("255")).Render("  [y] Yes    [n] No / Esc")
-
 	popup := popupStyle.Render(msg)
-	pw := lipgloss.Width(popup)
-	ph := lipgloss.Height(popup)
-
-	left := (width - pw) / 2
-	top := (height - ph) / 2
-	if left < 0 {
-		left = 0
-	}
-	if top < 0 {
-		top = 0
-	}
 
 	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, popup,
 		lipgloss.WithWhitespaceChars(" "),
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/moby/moby/client"
+)
+
+// Volume is one row of the volumes table.
+type Volume struct {
+	Name       string
+	Driver     string
+	Mountpoint string
+	Size       int64 // bytes, -1 when the daemon didn't report it
+}
+
+// ListVolumes lists named and anonymous volumes. Sizes come from the
+// daemon's disk usage report, which can be slow on large volumes, so a
+// failure there only leaves sizes unknown.
+func ListVolumes(cli *client.Client) ([]Volume, error) {
+	res, err := cli.VolumeList(context.Background(), client.VolumeListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := make(map[string]int64)
+	if du, err := cli.DiskUsage(context.Background(), client.DiskUsageOptions{Volumes: true, Verbose: true}); err == nil {
+		for _, v := range du.Volumes.Items {
+			if v.UsageData != nil {
+				sizes[v.Name] = v.UsageData.Size
+			}
+		}
+	}
+
+	var result []Volume
+	for _, v := range res.Items {
+		size, ok := sizes[v.Name]
+		if !ok {
+			size = -1
+		}
+		result = append(result, Volume{
+			Name:       v.Name,
+			Driver:     v.Driver,
+			Mountpoint: v.Mountpoint,
+			Size:       size,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+func RemoveVolume(cli *client.Client, name string) error {
+	_, err := cli.VolumeRemove(context.Background(), name, client.VolumeRemoveOptions{})
+	return err
+}
+
+// PruneVolumes removes every volume not used by a container, named ones
+// included.
+func PruneVolumes(cli *client.Client) (count int, reclaimed uint64, err error) {
+	res, err := cli.VolumePrune(context.Background(), client.VolumePruneOptions{All: true})
+	if err != nil {
+		return 0, 0, err
+	}
+	return len(res.Report.VolumesDeleted), res.Report.SpaceReclaimed, nil
+}
+
+// volumeUsers maps each volume name to the containers that mount it.
+func volumeUsers(containers []Container) map[string][]Container {
+	users := make(map[string][]Container)
+	for _, c := range containers {
+		for _, v := range c.Volumes {
+			users[v] = append(users[v], c)
+		}
+	}
+	return users
+}
+
+// orphanVolumes returns the volumes no container references, and their
+// combined size where known.
+func orphanVolumes(volumes []Volume, users map[string][]Container) ([]Volume, int64) {
+	var orphans []Volume
+	var size int64
+	for _, v := range volumes {
+		if len(users[v.Name]) == 0 {
+			orphans = append(orphans, v)
+			if v.Size > 0 {
+				size += v.Size
+			}
+		}
+	}
+	return orphans, size
+}
+
+type volumesMsg struct {
+	cli     *client.Client
+	volumes []Volume
+	err     error
+}
+
+// volumeActionMsg reports the result of a volume action for the footer.
+type volumeActionMsg struct {
+	cli  *client.Client
+	done string
+	err  error
+}
+
+func fetchVolumes(cli *client.Client) tea.Cmd {
+	return func() tea.Msg {
+		volumes, err := ListVolumes(cli)
+		return volumesMsg{cli, volumes, err}
+	}
+}
+
+func doVolumeAction(cli *client.Client, done string, fn func(*client.Client) error) tea.Cmd {
+	return func() tea.Msg {
+		return volumeActionMsg{cli, done, fn(cli)}
+	}
+}
+
+func pruneVolumes(cli *client.Client) tea.Cmd {
+	return func() tea.Msg {
+		count, reclaimed, err := PruneVolumes(cli)
+		return volumeActionMsg{cli, fmt.Sprintf("Pruned %d volume(s), reclaimed %s.", count, formatBytes(float64(reclaimed))), err}
+	}
+}
+
+// ── Volumes screen ─────────────────────────────────────────────────────
+
+// volumeBodyHeight is the number of table rows that fit on screen.
+func (m model) volumeBodyHeight() int {
+	// title + table header with its border, footer with its top margin
+	h := m.height - 1 - 3 - 2
+	if h < 1 {
+		h = 1
+	}
+	return h
+}
+
+func (m *model) moveVolumeCursor(delta int) {
+	m.volumeList.move(delta, len(m.volumes), m.volumeBodyHeight())
+}
+
+func (m model) currentVolume() (Volume, bool) {
+	if m.volumeList.cursor < len(m.volumes) {
+		return m.volumes[m.volumeList.cursor], true
+	}
+	return Volume{}, false
+}
+
+// selectVolumeUsers jumps to the container list with every container that
+// mounts v marked, showing stopped containers too.
+func (m *model) selectVolumeUsers(v Volume) {
+	users := volumeUsers(m.allContainers)[v.Name]
+	if len(users) == 0 {
+		m.statusMsg = "No container uses " + v.Name
+		m.statusTick = 3
+		return
+	}
+	m.selected = make(map[string]bool, len(users))
+	for _, c := range users {
+		m.selected[c.key()] = true
+		delete(m.collapsed, c.Project)
+	}
+	m.activeView = viewContainers
+	m.showAll = true
+	m.refilter()
+	m.tableOffset = 0
+	for i, r := range m.rows {
+		if !r.isHeader() && m.selected[r.container.key()] {
+			m.cursor = i
+			break
+		}
+	}
+	m.scrollToCursor()
+	m.statusMsg = fmt.Sprintf("Selected %d container(s) using %s", len(users), v.Name)
+	m.statusTick = 3
+}
+
+// renderVolumesView renders the full-screen volumes table.
+func (m model) renderVolumesView() string {
+	users := volumeUsers(m.allContainers)
+	orphans, orphanSize := orphanVolumes(m.volumes, users)
+
+	title := titleStyle.Render(fmt.Sprintf("Volumes (%d)", len(m.volumes)))
+	if len(orphans) > 0 {
+		title += "  " + volumeOrphanStyle.Render(fmt.Sprintf("%d unused, %s", len(orphans), formatBytes(float64(orphanSize))))
+	}
+	if m.volumesErr != nil {
+		title += "  " + statusExitedStyle.Render("✖ "+m.volumesErr.Error())
+	}
+
+	available := m.width - 10
+	if available < 40 {
+		available = 40
+	}
+	wDriver, wSize := 10, 10
+	rest := available - wDriver - wSize
+	if rest < 60 {
+		rest = 60
+	}
+	wName := rest * 35 / 100
+	wMount := rest * 35 / 100
+	wUsers := rest - wName - wMount
+
+	tHeader := tableStyle.Render(lipgloss.JoinHorizontal(lipgloss.Top,
+		ListItemStyle.Width(2).Render(""),
+		ListItemStyle.Width(wName).Render("Name"),
+		ListItemStyle.Width(wDriver).Render("Driver"),
+		ListItemStyle.Width(wSize).Render("Size"),
+		ListItemStyle.Width(wMount).Render("Mountpoint"),
+		ListItemStyle.Width(wUsers).Render("Used by"),
+	))
+
+	bodyH := m.volumeBodyHeight()
+	start, end := m.volumeList.window(len(m.volumes), bodyH)
+
+	var rows []string
+	if len(m.volumes) == 0 {
+		rows = append(rows, "No volumes found.")
+	}
+	for i := start; i < end; i++ {
+		v := m.volumes[i]
+		selected := i == m.volumeList.cursor
+		style := ListItemStyle
+		if selected {
+			style = selectedStyle
+		} else if i%2 == 0 {
+			style = style.Copy().Background(rowEvenBg) // Zebra stripe
+		}
+		cursor := "  "
+		if selected {
+			cursor = "> "
+		}
+
+		var names []string
+		for _, c := range users[v.Name] {
+			names = append(names, c.Names)
+		}
+		name := truncate(v.Name, wName-2)
+		used := truncate(strings.Join(names, ", "), wUsers-2)
+		if len(names) == 0 {
+			used = "(unused)"
+			if !selected {
+				name = volumeOrphanStyle.Render(name)
+				used = volumeOrphanStyle.Render(used)
+			}
+		}
+		size := "-"
+		if v.Size >= 0 {
+			size = formatBytesShort(float64(v.Size))
+		}
+		mount := truncate(v.Mountpoint, wMount-2)
+		if !selected {
+			mount = imageStyle.Render(mount)
+		}
+
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top,
+			style.Render(cursor),
+			style.Width(wName).Render(name),
+			style.Width(wDriver).Render(v.Driver),
+			style.Width(wSize).Render(size),
+			style.Width(wMount).Render(mount),
+			style.Width(wUsers).Render(used),
+		))
+	}
+	for len(rows) < bodyH {
+		rows = append(rows, "")
+	}
+
+	footerText := m.keys.footer("volumes")
+	if m.statusMsg != "" {
+		footerText = m.statusMsg
+	}
+	return lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		tHeader,
+		lipgloss.JoinVertical(lipgloss.Left, rows...),
+		helpStyle.Render(footerText),
+	)
+}